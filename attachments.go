@@ -1,6 +1,7 @@
 package couchdb
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -16,6 +17,11 @@ type IndependAttachment struct {
 }
 
 func (db *Database) IndependAttachment(docid, name, rev string) (*IndependAttachment, error) {
+	return db.IndependAttachmentContext(context.Background(), docid, name, rev)
+}
+
+// IndependAttachmentContext is like IndependAttachment but uses ctx for the request.
+func (db *Database) IndependAttachmentContext(ctx context.Context, docid, name, rev string) (*IndependAttachment, error) {
 	if docid == "" {
 		return nil, fmt.Errorf("couchdb.GetAttachment: empty docid")
 	}
@@ -28,7 +34,7 @@ func (db *Database) IndependAttachment(docid, name, rev string) (*IndependAttach
 	} else {
 		u = fmt.Sprintf("%s/%s/%s/%s?rev=%s", db.Host, url.PathEscape(db.Name), url.PathEscape(docid), url.PathEscape(name), url.PathEscape(rev))
 	}
-	resp, err := db.Client.GetRaw(u)
+	resp, err := db.Client.GetRawContext(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +48,11 @@ func (db *Database) IndependAttachment(docid, name, rev string) (*IndependAttach
 }
 
 func (db *Database) IndependAttachmentMeta(docid, name, rev string) (*IndependAttachment, error) {
+	return db.IndependAttachmentMetaContext(context.Background(), docid, name, rev)
+}
+
+// IndependAttachmentMetaContext is like IndependAttachmentMeta but uses ctx for the request.
+func (db *Database) IndependAttachmentMetaContext(ctx context.Context, docid, name, rev string) (*IndependAttachment, error) {
 	if docid == "" {
 		return nil, fmt.Errorf("couchdb.GetAttachment: empty docid")
 	}
@@ -54,7 +65,7 @@ func (db *Database) IndependAttachmentMeta(docid, name, rev string) (*IndependAt
 	} else {
 		u = fmt.Sprintf("%s/%s/%s/%s?rev=%s", db.Host, url.PathEscape(db.Name), url.PathEscape(docid), url.PathEscape(name), url.PathEscape(rev))
 	}
-	resp, err := db.Client.Head(u)
+	resp, err := db.Client.HeadContext(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +89,11 @@ func attFromHeaders(name string, resp *http.Response) (*IndependAttachment, erro
 }
 
 func (db *Database) PutIndependAttachment(docid string, att *IndependAttachment, rev string) (*DocumentResponse, error) {
+	return db.PutIndependAttachmentContext(context.Background(), docid, att, rev)
+}
+
+// PutIndependAttachmentContext is like PutIndependAttachment but uses ctx for the request.
+func (db *Database) PutIndependAttachmentContext(ctx context.Context, docid string, att *IndependAttachment, rev string) (*DocumentResponse, error) {
 	if docid == "" {
 		return nil, fmt.Errorf("couchdb.PutAttachment: empty docid")
 	}
@@ -96,11 +112,16 @@ func (db *Database) PutIndependAttachment(docid string, att *IndependAttachment,
 	}
 
 	response := &DocumentResponse{}
-	err := db.Client.PutWithData(u, att.Body, response, att.Type)
+	err := db.Client.PutWithDataContext(ctx, u, att.Body, response, att.Type)
 	return response, err
 }
 
 func (db *Database) DeleteIndependAttachment(docid, name, rev string) (*DocumentResponse, error) {
+	return db.DeleteIndependAttachmentContext(context.Background(), docid, name, rev)
+}
+
+// DeleteIndependAttachmentContext is like DeleteIndependAttachment but uses ctx for the request.
+func (db *Database) DeleteIndependAttachmentContext(ctx context.Context, docid, name, rev string) (*DocumentResponse, error) {
 	if docid == "" {
 		return nil, fmt.Errorf("couchdb.PutAttachment: empty docid")
 	}
@@ -110,6 +131,6 @@ func (db *Database) DeleteIndependAttachment(docid, name, rev string) (*Document
 
 	u := fmt.Sprintf("%s/%s/%s/%s?rev=%s", db.Host, url.PathEscape(db.Name), url.PathEscape(docid), url.PathEscape(name), url.PathEscape(rev))
 	response := &DocumentResponse{}
-	err := db.Client.Delete(u, response)
+	err := db.Client.DeleteContext(ctx, u, response)
 	return response, err
 }
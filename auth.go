@@ -0,0 +1,167 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Authenticator adds credentials to every request sent by a CouchDBClient.
+// http://docs.couchdb.org/en/stable/api/server/authn.html
+type Authenticator interface {
+	Authenticate(c *CouchDBClient, req *http.Request) error
+}
+
+// Reauthenticator is implemented by authenticators holding a session that can expire.
+// After a 401 response the client calls Reauthenticate and retries the request once.
+type Reauthenticator interface {
+	Reauthenticate(ctx context.Context, c *CouchDBClient) error
+}
+
+// responseObserver is implemented by authenticators that pick up refreshed credentials from responses.
+type responseObserver interface {
+	observe(resp *http.Response)
+}
+
+// NoAuth sends requests without credentials.
+type NoAuth struct{}
+
+func (NoAuth) Authenticate(c *CouchDBClient, req *http.Request) error {
+	return nil
+}
+
+// BasicAuth sends the username and password with every request.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (a *BasicAuth) Authenticate(c *CouchDBClient, req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// CookieAuth logs in once with POST /_session and sends the AuthSession cookie afterwards.
+// The cookie is replaced whenever CouchDB refreshes it and the session is
+// re-established after a 401 response.
+// http://docs.couchdb.org/en/stable/api/server/authn.html#cookie-authentication
+type CookieAuth struct {
+	Username string
+	Password string
+
+	mu     sync.Mutex
+	cookie *http.Cookie
+}
+
+// NewCookieAuth returns a CookieAuth for the given credentials.
+func NewCookieAuth(username, password string) *CookieAuth {
+	return &CookieAuth{Username: username, Password: password}
+}
+
+func (a *CookieAuth) Authenticate(c *CouchDBClient, req *http.Request) error {
+	a.mu.Lock()
+	cookie := a.cookie
+	a.mu.Unlock()
+	if cookie == nil {
+		if err := a.Reauthenticate(req.Context(), c); err != nil {
+			return err
+		}
+		a.mu.Lock()
+		cookie = a.cookie
+		a.mu.Unlock()
+	}
+	req.AddCookie(cookie)
+	return nil
+}
+
+// Reauthenticate creates a new session with POST /_session.
+func (a *CookieAuth) Reauthenticate(ctx context.Context, c *CouchDBClient) error {
+	body, err := json.Marshal(map[string]string{"name": a.Username, "password": a.Password})
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("%s/_session", c.host), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode > 299 {
+		return parseError(req, resp)
+	}
+	resp.Body.Close()
+	if !a.setCookie(resp) {
+		return fmt.Errorf("couchdb: missing AuthSession cookie in response")
+	}
+	return nil
+}
+
+func (a *CookieAuth) observe(resp *http.Response) {
+	a.setCookie(resp)
+}
+
+func (a *CookieAuth) setCookie(resp *http.Response) bool {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "AuthSession" && cookie.Value != "" {
+			a.mu.Lock()
+			a.cookie = &http.Cookie{Name: cookie.Name, Value: cookie.Value}
+			a.mu.Unlock()
+			return true
+		}
+	}
+	return false
+}
+
+// JWTAuth sends a bearer token obtained from TokenSource with every request.
+// http://docs.couchdb.org/en/stable/api/server/authn.html#jwt-authentication
+type JWTAuth struct {
+	TokenSource func(ctx context.Context) (string, error)
+}
+
+func (a *JWTAuth) Authenticate(c *CouchDBClient, req *http.Request) error {
+	token, err := a.TokenSource(req.Context())
+	if err != nil {
+		return fmt.Errorf("couchdb: get jwt token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// ProxyAuth identifies the user through the X-Auth-CouchDB-* headers
+// set by a trusted authentication proxy.
+// http://docs.couchdb.org/en/stable/api/server/authn.html#proxy-authentication
+type ProxyAuth struct {
+	Username string
+	Roles    []string
+	Token    string // Optional, see ProxyToken
+}
+
+func (a *ProxyAuth) Authenticate(c *CouchDBClient, req *http.Request) error {
+	req.Header.Set("X-Auth-CouchDB-UserName", a.Username)
+	if len(a.Roles) > 0 {
+		req.Header.Set("X-Auth-CouchDB-Roles", strings.Join(a.Roles, ","))
+	}
+	if a.Token != "" {
+		req.Header.Set("X-Auth-CouchDB-Token", a.Token)
+	}
+	return nil
+}
+
+// ProxyToken computes the X-Auth-CouchDB-Token value for username, that is
+// the hex encoded HMAC of username keyed with the server's [chttpd_auth] secret.
+// h must match the server's hash algorithm, e.g. sha256.New.
+func ProxyToken(h func() hash.Hash, secret, username string) string {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write([]byte(username))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,108 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// sessionServer is a CouchDB stand-in for cookie authentication whose sessions can be expired.
+type sessionServer struct {
+	mu       sync.Mutex
+	sessions map[string]bool
+	logins   int
+	refresh  bool // refresh the cookie with the next response
+	bodies   []string
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.URL.Path == "/_session" {
+		var creds map[string]string
+		json.NewDecoder(r.Body).Decode(&creds)
+		if creds["name"] != "admin" || creds["password"] != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"unauthorized","reason":"Name or password is incorrect."}`))
+			return
+		}
+		s.logins++
+		session := fmt.Sprintf("login%d", s.logins)
+		s.sessions[session] = true
+		http.SetCookie(w, &http.Cookie{Name: "AuthSession", Value: session})
+		w.Write([]byte(`{"ok":true}`))
+		return
+	}
+	cookie, err := r.Cookie("AuthSession")
+	if err != nil || !s.sessions[cookie.Value] {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"unauthorized","reason":"You are not authorized to access this db."}`))
+		return
+	}
+	if s.refresh {
+		s.refresh = false
+		delete(s.sessions, cookie.Value)
+		s.sessions["refreshed"] = true
+		http.SetCookie(w, &http.Cookie{Name: "AuthSession", Value: "refreshed"})
+	}
+	b, _ := ioutil.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(b))
+	w.Write([]byte(`{"ok":true,"id":"a","rev":"1-x"}`))
+}
+
+func (s *sessionServer) expire() {
+	s.mu.Lock()
+	s.sessions = map[string]bool{}
+	s.mu.Unlock()
+}
+
+func TestCookieAuth(t *testing.T) {
+	s := &sessionServer{sessions: map[string]bool{}}
+	db := testDatabase(t, s.ServeHTTP, WithAuth(NewCookieAuth("admin", "secret")))
+	ctx := context.Background()
+
+	if _, err := db.PutContext(ctx, &Document{ID: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if s.logins != 1 {
+		t.Errorf("%d logins before the first request, want 1", s.logins)
+	}
+
+	// the session expires; the request is replayed with its body after a new login
+	s.expire()
+	if _, err := db.PutContext(ctx, &Document{ID: "a", Rev: "1-x"}); err != nil {
+		t.Fatal(err)
+	}
+	if s.logins != 2 {
+		t.Errorf("%d logins after expiry, want 2", s.logins)
+	}
+	if len(s.bodies) != 2 || s.bodies[1] != `{"_id":"a","_rev":"1-x"}` {
+		t.Errorf("bodies = %q", s.bodies)
+	}
+
+	// a refreshed cookie is used by the following requests without logging in again
+	s.mu.Lock()
+	s.refresh = true
+	s.mu.Unlock()
+	for i := 0; i < 2; i++ {
+		if _, err := db.PutContext(ctx, &Document{ID: "a"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.logins != 2 {
+		t.Errorf("%d logins after refresh, want 2", s.logins)
+	}
+}
+
+func TestCookieAuthWrongPassword(t *testing.T) {
+	s := &sessionServer{sessions: map[string]bool{}}
+	db := testDatabase(t, s.ServeHTTP, WithAuth(NewCookieAuth("admin", "wrong")))
+	_, err := db.PutContext(context.Background(), &Document{ID: "a"})
+	if !Unauthorized(err) {
+		t.Errorf("err = %v, want 401", err)
+	}
+}
@@ -0,0 +1,208 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// BulkGetRef selects a document, optionally at a revision, for BulkGet.
+type BulkGetRef struct {
+	ID        string   `json:"id"`
+	Rev       string   `json:"rev,omitempty"`
+	AttsSince []string `json:"atts_since,omitempty"`
+}
+
+// BulkGetOptions are the query parameters of POST /{db}/_bulk_get.
+type BulkGetOptions struct {
+	Revs        bool // Include the _revisions history
+	Latest      bool // Return the latest leaf revision instead of the requested one
+	Attachments bool // Include attachment content
+	Multipart   bool // Request multipart/mixed, which carries attachments as binary parts instead of base64
+}
+
+// BulkGetResult holds the documents returned for one requested ID.
+type BulkGetResult struct {
+	ID   string       `json:"id"`
+	Docs []BulkGetDoc `json:"docs"`
+}
+
+// BulkGetDoc is either a fetched document revision or an error.
+type BulkGetDoc struct {
+	OK    json.RawMessage `json:"ok,omitempty"`
+	Error *BulkGetError   `json:"error,omitempty"`
+
+	// Attachments holds the content of attachments received as
+	// separate parts of a multipart response, by attachment name.
+	Attachments map[string][]byte `json:"-"`
+}
+
+// BulkGetError describes why a document revision could not be fetched.
+// It matches the sentinel errors with errors.Is like *Error.
+type BulkGetError struct {
+	ID        string `json:"id"`
+	Rev       string `json:"rev"`
+	ErrorCode string `json:"error"`
+	Reason    string `json:"reason"`
+}
+
+func (e *BulkGetError) Error() string {
+	return fmt.Sprintf("couchdb: bulk get %s %s: %s: %s", e.ID, e.Rev, e.ErrorCode, e.Reason)
+}
+
+func (e *BulkGetError) Is(target error) bool {
+	return errorCodes[e.ErrorCode] == target
+}
+
+// Missing reports whether the document or revision does not exist.
+func (d *BulkGetDoc) Missing() bool {
+	return d.Error != nil && d.Error.ErrorCode == "not_found"
+}
+
+// ScanDoc decodes the fetched document into v.
+func (d *BulkGetDoc) ScanDoc(v interface{}) error {
+	if d.Error != nil {
+		return d.Error
+	}
+	return json.Unmarshal(d.OK, v)
+}
+
+// BulkGet fetches several documents in one request.
+// http://docs.couchdb.org/en/stable/api/database/bulk-api.html#db-bulk-get
+func (db *Database) BulkGet(ctx context.Context, refs []BulkGetRef, opts *BulkGetOptions) ([]BulkGetResult, error) {
+	q := url.Values{}
+	var header http.Header
+	if opts != nil {
+		if opts.Revs {
+			q.Set("revs", "true")
+		}
+		if opts.Latest {
+			q.Set("latest", "true")
+		}
+		if opts.Attachments {
+			q.Set("attachments", "true")
+		}
+		if opts.Multipart {
+			header = http.Header{"Accept": {"multipart/mixed"}}
+		}
+	}
+	u := fmt.Sprintf("%s/%s/_bulk_get?%s", db.Host, url.PathEscape(db.Name), q.Encode())
+	content := struct {
+		Docs []BulkGetRef `json:"docs"`
+	}{
+		Docs: refs,
+	}
+	resp, err := db.Client.openResponse(ctx, u, "POST", content, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err == nil && mediaType == "multipart/mixed" {
+		return readBulkGetMultipart(multipart.NewReader(resp.Body, params["boundary"]))
+	}
+	response := struct {
+		Results []BulkGetResult `json:"results"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+// readBulkGetMultipart reads a multipart/mixed _bulk_get response. Every part is
+// a JSON document, a JSON error or a multipart/related document with attachments.
+func readBulkGetMultipart(r *multipart.Reader) ([]BulkGetResult, error) {
+	var results []BulkGetResult
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return results, nil
+		} else if err != nil {
+			return nil, err
+		}
+		id, doc, err := readBulkGetPart(part)
+		part.Close()
+		if err != nil {
+			return nil, err
+		}
+		if n := len(results); n > 0 && results[n-1].ID == id {
+			results[n-1].Docs = append(results[n-1].Docs, doc)
+		} else {
+			results = append(results, BulkGetResult{ID: id, Docs: []BulkGetDoc{doc}})
+		}
+	}
+}
+
+func readBulkGetPart(part *multipart.Part) (string, BulkGetDoc, error) {
+	mediaType, params, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+	if err != nil {
+		return "", BulkGetDoc{}, err
+	}
+	if mediaType != "multipart/related" {
+		return readBulkGetJSON(part)
+	}
+	related := multipart.NewReader(part, params["boundary"])
+	first, err := related.NextPart()
+	if err != nil {
+		return "", BulkGetDoc{}, err
+	}
+	id, doc, err := readBulkGetJSON(first)
+	if err != nil {
+		return "", BulkGetDoc{}, err
+	}
+	for {
+		att, err := related.NextPart()
+		if err == io.EOF {
+			return id, doc, nil
+		} else if err != nil {
+			return "", BulkGetDoc{}, err
+		}
+		b, err := ioutil.ReadAll(att)
+		if err != nil {
+			return "", BulkGetDoc{}, err
+		}
+		if doc.Attachments == nil {
+			doc.Attachments = map[string][]byte{}
+		}
+		doc.Attachments[attachmentName(att)] = b
+	}
+}
+
+func readBulkGetJSON(r io.Reader) (string, BulkGetDoc, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", BulkGetDoc{}, err
+	}
+	var probe struct {
+		ID    string `json:"_id"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return "", BulkGetDoc{}, err
+	}
+	if probe.ID == "" && probe.Error != "" {
+		e := &BulkGetError{}
+		if err := json.Unmarshal(b, e); err != nil {
+			return "", BulkGetDoc{}, err
+		}
+		return e.ID, BulkGetDoc{Error: e}, nil
+	}
+	return probe.ID, BulkGetDoc{OK: b}, nil
+}
+
+// attachmentName returns the file name from the Content-Disposition header of an attachment part.
+func attachmentName(part *multipart.Part) string {
+	if name := part.FileName(); name != "" {
+		return name
+	}
+	_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+	return strings.Trim(params["filename"], `"`)
+}
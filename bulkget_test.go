@@ -0,0 +1,91 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+var bulkGetRefs = []BulkGetRef{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+func checkBulkGet(t *testing.T, results []BulkGetResult) {
+	t.Helper()
+	if len(results) != 3 {
+		t.Fatalf("%d results, want 3", len(results))
+	}
+	for i, id := range []string{"a", "b", "c"} {
+		if results[i].ID != id || len(results[i].Docs) != 1 {
+			t.Fatalf("results[%d] = %+v", i, results[i])
+		}
+	}
+	var doc Document
+	if err := results[0].Docs[0].ScanDoc(&doc); err != nil || doc.Rev != "1-x" {
+		t.Errorf("ScanDoc() = %v, doc = %+v", err, doc)
+	}
+	missing := results[2].Docs[0]
+	if !missing.Missing() {
+		t.Error("Missing() = false for not found document")
+	}
+	if err := missing.ScanDoc(&doc); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ScanDoc() = %v, want ErrNotFound", err)
+	}
+}
+
+func TestBulkGetJSON(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/db/_bulk_get" || r.URL.Query().Get("revs") != "true" {
+			t.Errorf("request = %s %s", r.Method, r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[
+			{"id":"a","docs":[{"ok":{"_id":"a","_rev":"1-x"}}]},
+			{"id":"b","docs":[{"ok":{"_id":"b","_rev":"1-y"}}]},
+			{"id":"c","docs":[{"error":{"id":"c","rev":"undefined","error":"not_found","reason":"missing"}}]}
+		]}`))
+	})
+	results, err := db.BulkGet(context.Background(), bulkGetRefs, &BulkGetOptions{Revs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBulkGet(t, results)
+}
+
+func TestBulkGetMultipart(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "multipart/mixed" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", `multipart/mixed; boundary="outer"`)
+		w.Write([]byte("--outer\r\n" +
+			"Content-Type: application/json\r\n\r\n" +
+			`{"_id":"a","_rev":"1-x"}` + "\r\n" +
+			"--outer\r\n" +
+			"Content-Type: multipart/related; boundary=\"inner\"\r\n\r\n" +
+			"--inner\r\n" +
+			"Content-Type: application/json\r\n\r\n" +
+			`{"_id":"b","_rev":"1-y","_attachments":{"hello.txt":{"content_type":"text/plain","follows":true,"length":5}}}` + "\r\n" +
+			"--inner\r\n" +
+			"Content-Disposition: attachment; filename=\"hello.txt\"\r\n" +
+			"Content-Type: text/plain\r\n\r\n" +
+			"hello\r\n" +
+			"--inner--\r\n" +
+			"--outer\r\n" +
+			"Content-Type: application/json; error=\"true\"\r\n\r\n" +
+			`{"id":"c","rev":"undefined","error":"not_found","reason":"missing"}` + "\r\n" +
+			"--outer--"))
+	})
+	results, err := db.BulkGet(context.Background(), bulkGetRefs, &BulkGetOptions{Attachments: true, Multipart: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBulkGet(t, results)
+	related := results[1].Docs[0]
+	var doc Document
+	if err := related.ScanDoc(&doc); err != nil || doc.ID != "b" {
+		t.Errorf("ScanDoc() = %v, doc = %+v", err, doc)
+	}
+	if got := string(related.Attachments["hello.txt"]); got != "hello" {
+		t.Errorf("attachment = %q, want %q", got, "hello")
+	}
+}
@@ -0,0 +1,397 @@
+package couchdb
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Feed types of the changes feed.
+const (
+	FeedNormal      = "normal"
+	FeedLongpoll    = "longpoll"
+	FeedContinuous  = "continuous"
+	FeedEventSource = "eventsource"
+)
+
+// Built-in filters of the changes feed.
+// http://docs.couchdb.org/en/stable/api/database/changes.html#filtering
+const (
+	FilterDocIDs   = "_doc_ids"
+	FilterSelector = "_selector"
+	FilterDesign   = "_design"
+	FilterView     = "_view"
+)
+
+// ChangesOptions are the query parameters of GET /{db}/_changes.
+// Setting DocIDs or Selector sends the request as POST with the respective
+// filter, see also UseFilter and UseView for design document filters.
+// http://docs.couchdb.org/en/stable/api/database/changes.html
+type ChangesOptions struct {
+	Feed        string        // One of the Feed* constants, defaults to FeedNormal
+	Since       Seq           // Start after this sequence, "now" or empty for the beginning
+	LastEventID string        // Sent as Last-Event-ID header to resume an eventsource feed
+	Heartbeat   time.Duration // Interval of empty lines keeping longpoll and continuous feeds alive
+	Timeout     time.Duration // Time to wait for changes before the response ends
+	IncludeDocs bool
+	Style       string // "main_only" (default) or "all_docs"
+	Conflicts   bool
+	Descending  bool
+	Limit       int
+
+	Filter   string                 // A Filter* constant or "ddoc/filtername"
+	DocIDs   []string               // Document IDs for FilterDocIDs
+	Selector map[string]interface{} // Mango selector for FilterSelector, like FindArgs.Selector
+	View     string                 // "ddoc/view" whose map function filters for FilterView
+	Params   map[string]string      // Additional query parameters passed to custom filter functions
+}
+
+// UseFilter selects the filter function name of the design document dd.
+func (o *ChangesOptions) UseFilter(dd DesignDocument, name string) error {
+	if _, ok := dd.Filters[name]; !ok {
+		return fmt.Errorf("couchdb: design document %s has no filter %q", dd.ID, name)
+	}
+	o.Filter = dd.Name() + "/" + name
+	return nil
+}
+
+// UseView selects the map function of view name of the design document dd as filter.
+func (o *ChangesOptions) UseView(dd DesignDocument, name string) error {
+	if _, ok := dd.Views[name]; !ok {
+		return fmt.Errorf("couchdb: design document %s has no view %q", dd.ID, name)
+	}
+	o.Filter = FilterView
+	o.View = dd.Name() + "/" + name
+	return nil
+}
+
+// filter returns the filter parameter, defaulting to the filter implied by DocIDs or Selector.
+func (o *ChangesOptions) filter() string {
+	switch {
+	case o.Filter != "":
+		return o.Filter
+	case len(o.DocIDs) > 0:
+		return FilterDocIDs
+	case o.Selector != nil:
+		return FilterSelector
+	}
+	return ""
+}
+
+// body returns the POST body for the doc_ids and selector filters, or nil for a GET request.
+func (o *ChangesOptions) body() interface{} {
+	if o == nil {
+		return nil
+	}
+	switch o.filter() {
+	case FilterDocIDs:
+		return map[string]interface{}{"doc_ids": o.DocIDs}
+	case FilterSelector:
+		return map[string]interface{}{"selector": o.Selector}
+	}
+	return nil
+}
+
+func (o *ChangesOptions) values() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.Feed != "" {
+		q.Set("feed", o.Feed)
+	}
+	if o.Since != "" {
+		q.Set("since", string(o.Since))
+	}
+	if o.Heartbeat > 0 {
+		q.Set("heartbeat", strconv.FormatInt(int64(o.Heartbeat/time.Millisecond), 10))
+	}
+	if o.Timeout > 0 {
+		q.Set("timeout", strconv.FormatInt(int64(o.Timeout/time.Millisecond), 10))
+	}
+	if o.IncludeDocs {
+		q.Set("include_docs", "true")
+	}
+	if o.Style != "" {
+		q.Set("style", o.Style)
+	}
+	if o.Conflicts {
+		q.Set("conflicts", "true")
+	}
+	if o.Descending {
+		q.Set("descending", "true")
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if filter := o.filter(); filter != "" {
+		q.Set("filter", filter)
+	}
+	if o.View != "" {
+		q.Set("view", o.View)
+	}
+	for k, v := range o.Params {
+		q.Set(k, v)
+	}
+	return q
+}
+
+// Change is a single row of the changes feed.
+type Change struct {
+	Seq     Seq             `json:"seq"`
+	ID      string          `json:"id"`
+	Changes []ChangeRev     `json:"changes"`
+	Deleted bool            `json:"deleted,omitempty"`
+	Doc     json.RawMessage `json:"doc,omitempty"`
+}
+
+// ChangeRev is a leaf revision listed in a Change.
+type ChangeRev struct {
+	Rev string `json:"rev"`
+}
+
+// ScanDoc decodes the document included with include_docs into v.
+func (c *Change) ScanDoc(v interface{}) error {
+	if len(c.Doc) == 0 {
+		return fmt.Errorf("couchdb: change %s has no document", c.ID)
+	}
+	return json.Unmarshal(c.Doc, v)
+}
+
+// Changes iterates over the rows of a changes feed.
+// Rows are read from the response as Next is called, so a slow consumer
+// holds back the server instead of buffering the feed in memory.
+// Cancel the context passed to Database.Changes or call Close to stop early.
+//
+//	changes, err := db.Changes(ctx, &couchdb.ChangesOptions{Feed: couchdb.FeedContinuous})
+//	if err != nil {
+//		return err
+//	}
+//	defer changes.Close()
+//	for changes.Next() {
+//		change := changes.Change()
+//	}
+//	return changes.Err()
+type Changes struct {
+	ctx     context.Context
+	body    io.ReadCloser
+	read    func() (*Change, error)
+	change  *Change
+	lastSeq Seq
+	pending int64
+	err     error
+	done    bool
+}
+
+// Changes opens the changes feed of the database.
+// http://docs.couchdb.org/en/stable/api/database/changes.html
+func (db *Database) Changes(ctx context.Context, opts *ChangesOptions) (*Changes, error) {
+	u := fmt.Sprintf("%s/%s/_changes?%s", db.Host, url.PathEscape(db.Name), opts.values().Encode())
+	var header http.Header
+	if opts != nil && opts.LastEventID != "" {
+		header = http.Header{"Last-Event-Id": {opts.LastEventID}}
+	}
+	method, in := "GET", opts.body()
+	if in != nil {
+		method = "POST"
+	}
+	body, err := db.Client.openWithHeader(ctx, u, method, in, header)
+	if err != nil {
+		return nil, err
+	}
+	feed := FeedNormal
+	if opts != nil && opts.Feed != "" {
+		feed = opts.Feed
+	}
+	return newChanges(ctx, body, feed), nil
+}
+
+func newChanges(ctx context.Context, body io.ReadCloser, feed string) *Changes {
+	c := &Changes{ctx: ctx, body: body}
+	switch feed {
+	case FeedContinuous:
+		c.read = c.continuousReader(json.NewDecoder(body))
+	case FeedEventSource:
+		c.read = c.eventSourceReader(bufio.NewReader(body))
+	default:
+		c.read = c.normalReader(json.NewDecoder(body))
+	}
+	return c
+}
+
+// Next advances to the next change. It returns false at the end of the feed or on error.
+func (c *Changes) Next() bool {
+	if c.done {
+		return false
+	}
+	change, err := c.read()
+	if err != nil {
+		if err != io.EOF {
+			if ctxErr := c.ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			c.err = err
+		}
+		c.change = nil
+		c.Close()
+		return false
+	}
+	c.change = change
+	if change.Seq != "" {
+		c.lastSeq = change.Seq
+	}
+	return true
+}
+
+// Change returns the current change.
+func (c *Changes) Change() *Change {
+	return c.change
+}
+
+// LastSeq returns the sequence of the latest change read, or
+// the last_seq reported by CouchDB once the feed has ended.
+func (c *Changes) LastSeq() Seq {
+	return c.lastSeq
+}
+
+// Pending returns the number of changes left after the feed ended, as reported by CouchDB.
+func (c *Changes) Pending() int64 {
+	return c.pending
+}
+
+// Err returns the error that ended the iteration, if any.
+func (c *Changes) Err() error {
+	return c.err
+}
+
+// Close releases the response. It must not be called concurrently with Next.
+func (c *Changes) Close() error {
+	if c.done {
+		return nil
+	}
+	c.done = true
+	return c.body.Close()
+}
+
+// changeLine is a line of the continuous and eventsource feeds,
+// which carry either a change or the final last_seq.
+type changeLine struct {
+	Change
+	LastSeq *Seq  `json:"last_seq"`
+	Pending int64 `json:"pending"`
+}
+
+func (c *Changes) line(l *changeLine) (*Change, error) {
+	if l.LastSeq != nil {
+		c.lastSeq = *l.LastSeq
+		c.pending = l.Pending
+		return nil, io.EOF
+	}
+	return &l.Change, nil
+}
+
+// normalReader reads the {"results": [...], "last_seq": ...} object of the normal and longpoll feeds.
+func (c *Changes) normalReader(dec *json.Decoder) func() (*Change, error) {
+	started, inResults := false, false
+	return func() (*Change, error) {
+		if !started {
+			if err := expectDelim(dec, '{'); err != nil {
+				return nil, err
+			}
+			started = true
+		}
+		for {
+			if inResults {
+				if dec.More() {
+					change := &Change{}
+					if err := dec.Decode(change); err != nil {
+						return nil, err
+					}
+					return change, nil
+				}
+				if err := expectDelim(dec, ']'); err != nil {
+					return nil, err
+				}
+				inResults = false
+				continue
+			}
+			if !dec.More() {
+				return nil, io.EOF
+			}
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch key {
+			case "results":
+				if err := expectDelim(dec, '['); err != nil {
+					return nil, err
+				}
+				inResults = true
+			case "last_seq":
+				err = dec.Decode(&c.lastSeq)
+			case "pending":
+				err = dec.Decode(&c.pending)
+			default:
+				err = dec.Decode(&json.RawMessage{})
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// continuousReader reads the newline separated objects of the continuous feed.
+func (c *Changes) continuousReader(dec *json.Decoder) func() (*Change, error) {
+	return func() (*Change, error) {
+		l := &changeLine{}
+		if err := dec.Decode(l); err != nil {
+			return nil, err
+		}
+		return c.line(l)
+	}
+}
+
+// eventSourceReader reads the "data: ..." events of the eventsource feed.
+func (c *Changes) eventSourceReader(r *bufio.Reader) func() (*Change, error) {
+	return func() (*Change, error) {
+		for {
+			s, err := r.ReadString('\n')
+			if err != nil && s == "" {
+				return nil, err
+			}
+			s = strings.TrimRight(s, "\r\n")
+			if !strings.HasPrefix(s, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(s, "data:"))
+			if data == "" {
+				continue
+			}
+			l := &changeLine{}
+			if err := json.Unmarshal([]byte(data), l); err != nil {
+				return nil, err
+			}
+			return c.line(l)
+		}
+	}
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("couchdb: unexpected JSON token %v, want %v", t, delim)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readChanges(t *testing.T, changes *Changes) []string {
+	t.Helper()
+	defer changes.Close()
+	var ids []string
+	for changes.Next() {
+		ids = append(ids, changes.Change().ID)
+	}
+	if err := changes.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	return ids
+}
+
+func TestChangesFeeds(t *testing.T) {
+	tests := []struct {
+		feed        string
+		contentType string
+		body        string
+	}{
+		{
+			feed:        FeedNormal,
+			contentType: "application/json",
+			body: `{"results":[
+				{"seq":"1-a","id":"a","changes":[{"rev":"1-x"}]},
+				{"seq":"2-b","id":"b","changes":[{"rev":"1-y"}],"deleted":true}
+			],"last_seq":"2-b","pending":3}`,
+		},
+		{
+			feed:        FeedLongpoll,
+			contentType: "application/json",
+			body:        `{"last_seq":"2-b","pending":3,"results":[{"seq":"1-a","id":"a"},{"seq":"2-b","id":"b"}]}`,
+		},
+		{
+			feed:        FeedContinuous,
+			contentType: "application/json",
+			body:        "{\"seq\":\"1-a\",\"id\":\"a\"}\n\n\n{\"seq\":\"2-b\",\"id\":\"b\"}\n\n{\"last_seq\":\"2-b\",\"pending\":3}\n",
+		},
+		{
+			feed:        FeedEventSource,
+			contentType: "text/event-stream",
+			body: "data: {\"seq\":\"1-a\",\"id\":\"a\"}\nid: 1-a\n\n" +
+				"event: heartbeat\ndata: \n\n" +
+				"data: {\"seq\":\"2-b\",\"id\":\"b\"}\nid: 2-b\n\n" +
+				"data: {\"last_seq\":\"2-b\",\"pending\":3}\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.feed, func(t *testing.T) {
+			db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("feed"); got != tt.feed {
+					t.Errorf("feed = %q, want %q", got, tt.feed)
+				}
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			})
+			changes, err := db.Changes(context.Background(), &ChangesOptions{Feed: tt.feed})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := readChanges(t, changes); !reflect.DeepEqual(ids, []string{"a", "b"}) {
+				t.Errorf("ids = %v", ids)
+			}
+			if changes.LastSeq() != "2-b" {
+				t.Errorf("LastSeq() = %q", changes.LastSeq())
+			}
+			if changes.Pending() != 3 {
+				t.Errorf("Pending() = %d", changes.Pending())
+			}
+		})
+	}
+}
+
+func TestChangesNumericSeq(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"seq":7,"id":"a"}],"last_seq":7}`))
+	})
+	changes, err := db.Changes(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readChanges(t, changes)
+	if changes.LastSeq() != "7" {
+		t.Errorf("LastSeq() = %q", changes.LastSeq())
+	}
+}
+
+func TestChangesHeartbeat(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("heartbeat"); got != "50" {
+			t.Errorf("heartbeat = %q", got)
+		}
+		flusher := w.(http.Flusher)
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("\n"))
+			flusher.Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+		w.Write([]byte("{\"seq\":\"1-a\",\"id\":\"a\"}\n\n{\"last_seq\":\"1-a\"}\n"))
+	})
+	changes, err := db.Changes(context.Background(), &ChangesOptions{Feed: FeedContinuous, Heartbeat: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := readChanges(t, changes); !reflect.DeepEqual(ids, []string{"a"}) {
+		t.Errorf("ids = %v", ids)
+	}
+}
+
+func TestChangesCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"seq\":\"1-a\",\"id\":\"a\"}\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := db.Changes(ctx, &ChangesOptions{Feed: FeedContinuous})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer changes.Close()
+	if !changes.Next() || changes.Change().ID != "a" {
+		t.Fatalf("Next() did not return the first change, Err() = %v", changes.Err())
+	}
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if changes.Next() {
+		t.Fatal("Next() = true after cancellation")
+	}
+	if !errors.Is(changes.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", changes.Err())
+	}
+}
+
+func TestChangesFilterBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   ChangesOptions
+		filter string
+		body   string
+	}{
+		{"doc_ids", ChangesOptions{DocIDs: []string{"a", "b"}}, FilterDocIDs, `{"doc_ids":["a","b"]}`},
+		{"selector", ChangesOptions{Selector: map[string]interface{}{"type": "user"}}, FilterSelector, `{"selector":{"type":"user"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" {
+					t.Errorf("method = %s, want POST", r.Method)
+				}
+				if got := r.URL.Query().Get("filter"); got != tt.filter {
+					t.Errorf("filter = %q, want %q", got, tt.filter)
+				}
+				b, _ := ioutil.ReadAll(r.Body)
+				var got, want interface{}
+				json.Unmarshal(b, &got)
+				json.Unmarshal([]byte(tt.body), &want)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("body = %s, want %s", b, tt.body)
+				}
+				w.Write([]byte(`{"results":[],"last_seq":"0"}`))
+			})
+			changes, err := db.Changes(context.Background(), &tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			readChanges(t, changes)
+		})
+	}
+}
+
+func TestChangesGetWithoutFilter(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("method = %s, want GET", r.Method)
+		}
+		w.Write([]byte(`{"results":[],"last_seq":"0"}`))
+	})
+	changes, err := db.Changes(context.Background(), &ChangesOptions{Since: "now"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readChanges(t, changes)
+}
@@ -0,0 +1,105 @@
+package couchdb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CheckpointStore persists the sequence up to which a Follower has processed the changes feed.
+type CheckpointStore interface {
+	// Load returns the saved sequence, or an empty Seq if there is none yet.
+	Load(ctx context.Context) (Seq, error)
+	Save(ctx context.Context, seq Seq) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory only.
+type MemoryCheckpointStore struct {
+	mu  sync.Mutex
+	seq Seq
+}
+
+func (s *MemoryCheckpointStore) Load(ctx context.Context) (Seq, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seq, nil
+}
+
+func (s *MemoryCheckpointStore) Save(ctx context.Context, seq Seq) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq = seq
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoint in a local file.
+// The file is replaced atomically on every Save.
+type FileCheckpointStore struct {
+	Path string
+}
+
+func (s *FileCheckpointStore) Load(ctx context.Context) (Seq, error) {
+	b, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return Seq(strings.TrimSpace(string(b))), nil
+}
+
+func (s *FileCheckpointStore) Save(ctx context.Context, seq Seq) error {
+	f, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(string(seq)); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), s.Path)
+}
+
+// LocalCheckpointStore keeps the checkpoint in the local document _local/{ID}
+// of a database, which is never replicated.
+// http://docs.couchdb.org/en/stable/api/local.html
+type LocalCheckpointStore struct {
+	DB DatabaseService
+	ID string
+}
+
+type checkpointDoc struct {
+	Document
+	Seq Seq `json:"seq"`
+}
+
+func (s *LocalCheckpointStore) Load(ctx context.Context) (Seq, error) {
+	doc := &checkpointDoc{}
+	err := s.DB.GetContext(ctx, doc, "_local/"+s.ID)
+	if NotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return doc.Seq, nil
+}
+
+func (s *LocalCheckpointStore) Save(ctx context.Context, seq Seq) error {
+	doc := &checkpointDoc{}
+	err := s.DB.GetContext(ctx, doc, "_local/"+s.ID)
+	if err != nil && !NotFound(err) {
+		return err
+	}
+	doc.ID = "_local/" + s.ID
+	doc.Seq = seq
+	_, err = s.DB.PutContext(ctx, doc)
+	return err
+}
@@ -2,24 +2,44 @@ package couchdb
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"strconv"
 )
 
 type CouchDBClient struct {
-	client *http.Client
-	host   string
-	user   string
-	pwd    string
+	client    *http.Client
+	host      string
+	auth      Authenticator
+	retry     *RetryPolicy
+	header    http.Header
+	userAgent string
+
+	httpOptions httpOptions
+	err         error // Reported by every request if the options are invalid
 }
 
-func NewClient(host, user, pwd string) *CouchDBClient {
-	return &CouchDBClient{http.DefaultClient, host, user, pwd}
+// NewClient returns a client for the CouchDB server at host.
+// Without options it uses http.DefaultClient and basic auth with user and pwd,
+// or no authentication if both are empty.
+func NewClient(host, user, pwd string, opts ...Option) *CouchDBClient {
+	c := &CouchDBClient{
+		host:   host,
+		auth:   &BasicAuth{Username: user, Password: pwd},
+		header: http.Header{},
+	}
+	if user == "" && pwd == "" {
+		c.auth = NoAuth{}
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.client, c.err = c.httpOptions.build()
+	return c
 }
 
 func (c *CouchDBClient) Use(name string) DatabaseService {
@@ -31,16 +51,16 @@ func (c *CouchDBClient) Use(name string) DatabaseService {
 }
 
 func (c *CouchDBClient) Head(rawurl string) (*http.Response, error) {
-	uri, err := url.Parse(rawurl)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("HEAD", uri.String(), nil)
+	return c.HeadContext(context.Background(), rawurl)
+}
+
+// HeadContext is like Head but uses ctx for the request.
+func (c *CouchDBClient) HeadContext(ctx context.Context, rawurl string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, "HEAD", rawurl, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.user, c.pwd)
-	resp, err := c.client.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	} else if resp.StatusCode >= 400 {
@@ -51,44 +71,72 @@ func (c *CouchDBClient) Head(rawurl string) (*http.Response, error) {
 }
 
 func (c *CouchDBClient) GetRaw(rawurl string) (*http.Response, error) {
-	uri, err := url.Parse(rawurl)
+	return c.GetRawContext(context.Background(), rawurl)
+}
+
+// GetRawContext is like GetRaw but uses ctx for the request.
+func (c *CouchDBClient) GetRawContext(ctx context.Context, rawurl string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, "GET", rawurl, nil)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", uri.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	req.SetBasicAuth(c.user, c.pwd)
-	resp, err := c.client.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
+	} else if resp.StatusCode >= 400 {
+		return nil, parseError(req, resp)
+	} else {
+		return resp, nil
 	}
-	return resp, nil
 }
 
 func (c *CouchDBClient) Get(rawurl string, out interface{}) error {
-	return c.do(rawurl, "GET", nil, out)
+	return c.GetContext(context.Background(), rawurl, out)
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (c *CouchDBClient) GetContext(ctx context.Context, rawurl string, out interface{}) error {
+	return c.do(ctx, rawurl, "GET", nil, out)
 }
 
 func (c *CouchDBClient) Post(rawurl string, in, out interface{}) error {
-	return c.do(rawurl, "POST", in, out)
+	return c.PostContext(context.Background(), rawurl, in, out)
+}
+
+// PostContext is like Post but uses ctx for the request.
+func (c *CouchDBClient) PostContext(ctx context.Context, rawurl string, in, out interface{}) error {
+	return c.do(ctx, rawurl, "POST", in, out)
 }
 
 func (c *CouchDBClient) Put(rawurl string, in, out interface{}) error {
-	return c.do(rawurl, "PUT", in, out)
+	return c.PutContext(context.Background(), rawurl, in, out)
+}
+
+// PutContext is like Put but uses ctx for the request.
+func (c *CouchDBClient) PutContext(ctx context.Context, rawurl string, in, out interface{}) error {
+	return c.do(ctx, rawurl, "PUT", in, out)
 }
 
 func (c *CouchDBClient) PutWithData(rawurl string, data io.Reader, out interface{}, contentType string) error {
-	return c.doWithoutEncode(rawurl, "PUT", data, out, contentType)
+	return c.PutWithDataContext(context.Background(), rawurl, data, out, contentType)
+}
+
+// PutWithDataContext is like PutWithData but uses ctx for the request.
+func (c *CouchDBClient) PutWithDataContext(ctx context.Context, rawurl string, data io.Reader, out interface{}, contentType string) error {
+	return c.doWithoutEncode(ctx, rawurl, "PUT", data, out, contentType)
 }
 
 func (c *CouchDBClient) Delete(rawurl string, out interface{}) error {
-	return c.do(rawurl, "DELETE", nil, out)
+	return c.DeleteContext(context.Background(), rawurl, out)
+}
+
+// DeleteContext is like Delete but uses ctx for the request.
+func (c *CouchDBClient) DeleteContext(ctx context.Context, rawurl string, out interface{}) error {
+	return c.do(ctx, rawurl, "DELETE", nil, out)
 }
 
-func (c *CouchDBClient) do(rawurl, method string, in, out interface{}) error {
-	body, err := c.open(rawurl, method, in)
+func (c *CouchDBClient) do(ctx context.Context, rawurl, method string, in, out interface{}) error {
+	body, err := c.open(ctx, rawurl, method, in)
 	if err != nil {
 		return err
 	}
@@ -101,8 +149,8 @@ func (c *CouchDBClient) do(rawurl, method string, in, out interface{}) error {
 	return nil
 }
 
-func (c *CouchDBClient) doWithoutEncode(rawurl, method string, in io.Reader, out interface{}, contentType string) error {
-	body, err := c.openWithoutEncode(rawurl, method, in, contentType)
+func (c *CouchDBClient) doWithoutEncode(ctx context.Context, rawurl, method string, in io.Reader, out interface{}, contentType string) error {
+	body, err := c.openWithoutEncode(ctx, rawurl, method, in, contentType)
 	if err != nil {
 		return err
 	}
@@ -113,8 +161,8 @@ func (c *CouchDBClient) doWithoutEncode(rawurl, method string, in io.Reader, out
 	return nil
 }
 
-func (c *CouchDBClient) doWithoutDecode(rawurl, method string, in interface{}) ([]byte, error) {
-	body, err := c.open(rawurl, method, in)
+func (c *CouchDBClient) doWithoutDecode(ctx context.Context, rawurl, method string, in interface{}) ([]byte, error) {
+	body, err := c.open(ctx, rawurl, method, in)
 	if err != nil {
 		return nil, err
 	}
@@ -123,62 +171,127 @@ func (c *CouchDBClient) doWithoutDecode(rawurl, method string, in interface{}) (
 	return out, nil
 }
 
-func (c *CouchDBClient) open(rawurl, method string, in interface{}) (io.ReadCloser, error) {
-	uri, err := url.Parse(rawurl)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest(method, uri.String(), nil)
+func (c *CouchDBClient) open(ctx context.Context, rawurl, method string, in interface{}) (io.ReadCloser, error) {
+	return c.openWithHeader(ctx, rawurl, method, in, nil)
+}
+
+// openWithHeader is like open but sends the additional request headers in header.
+func (c *CouchDBClient) openWithHeader(ctx context.Context, rawurl, method string, in interface{}, header http.Header) (io.ReadCloser, error) {
+	resp, err := c.openResponse(ctx, rawurl, method, in, header)
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.user, c.pwd)
+	return resp.Body, nil
+}
+
+// openResponse is like openWithHeader but returns the whole response, e.g. to inspect its Content-Type.
+func (c *CouchDBClient) openResponse(ctx context.Context, rawurl, method string, in interface{}, header http.Header) (*http.Response, error) {
+	var body io.Reader
 	if in != nil {
 		decoded, derr := json.Marshal(in)
 		if derr != nil {
 			return nil, derr
 		}
-		buf := bytes.NewBuffer(decoded)
-		req.Body = ioutil.NopCloser(buf)
-		req.ContentLength = int64(len(decoded))
-		req.Header.Set("Content-Length", strconv.Itoa(len(decoded)))
+		body = bytes.NewReader(decoded)
+	}
+	req, err := c.newRequest(ctx, method, rawurl, body)
+	if err != nil {
+		return nil, err
+	}
+	if in != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	resp, err := c.client.Do(req)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode > 299 {
+		return nil, parseError(req, resp)
+	}
+	return resp, nil
+}
+
+func (c *CouchDBClient) openWithoutEncode(ctx context.Context, rawurl, method string, in io.Reader, contentType string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, method, rawurl, in)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode > 299 {
-		defer resp.Body.Close()
-		out, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("client error %d: %s", resp.StatusCode, string(out))
+		return nil, parseError(req, resp)
 	}
 	return resp.Body, nil
 }
 
-func (c *CouchDBClient) openWithoutEncode(rawurl, method string, in io.Reader, contentType string) (io.ReadCloser, error) {
+// newRequest builds a request for rawurl carrying the client headers.
+func (c *CouchDBClient) newRequest(ctx context.Context, method, rawurl string, body io.Reader) (*http.Request, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
 	uri, err := url.Parse(rawurl)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(method, uri.String(), in)
+	req, err := http.NewRequestWithContext(ctx, method, uri.String(), body)
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.user, c.pwd)
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
+	for k, v := range c.header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	return req, nil
+}
+
+// sendAuthenticated authenticates and executes req with the underlying http.Client.
+// A 401 response is retried once if the authenticator can renew its session
+// and the request body can be replayed.
+func (c *CouchDBClient) sendAuthenticated(req *http.Request) (*http.Response, error) {
+	resp, err := c.authenticatedDo(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	r, ok := c.auth.(Reauthenticator)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+	resp.Body.Close()
+	if err := r.Reauthenticate(req.Context(), c); err != nil {
+		return nil, err
+	}
+	if req.GetBody != nil {
+		if req.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return c.authenticatedDo(req)
+}
+
+// authenticatedDo sends a copy of req with credentials added, leaving req itself untouched for a retry.
+func (c *CouchDBClient) authenticatedDo(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if err := c.auth.Authenticate(c, req); err != nil {
+		return nil, err
 	}
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode > 299 {
-		defer resp.Body.Close()
-		out, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("client error %d: %s", resp.StatusCode, string(out))
+	if o, ok := c.auth.(responseObserver); ok {
+		o.observe(resp)
 	}
-	return resp.Body, nil
+	return resp, nil
 }
 
 func handleRsp(rsp *http.Response, err error) ([]byte, error) {
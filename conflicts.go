@@ -0,0 +1,218 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConflictsView emits the conflicting revisions of every document that has any,
+// keyed by document ID. Add it to a design document to use Conflicts.
+var ConflictsView = DesignDocumentView{
+	Map: "function (doc) { if (doc._conflicts) { emit(doc._id, doc._conflicts); } }",
+}
+
+// ConflictsDesignDocument returns a design document _design/name with ConflictsView
+// as its view "conflicts", e.g. to pass to Seed.
+func ConflictsDesignDocument(name string) DesignDocument {
+	return DesignDocument{
+		Document: Document{ID: "_design/" + name},
+		Language: "javascript",
+		Views:    map[string]DesignDocumentView{"conflicts": ConflictsView},
+	}
+}
+
+// ConflictedDoc is a document with conflicting revisions.
+type ConflictedDoc struct {
+	ID        string
+	Conflicts []string // The losing leaf revisions
+}
+
+// Conflicts lists the documents with conflicts using the view "conflicts"
+// of the design document design, see ConflictsDesignDocument.
+func (db *Database) Conflicts(ctx context.Context, design string, params QueryParameters) ([]ConflictedDoc, error) {
+	response, err := QueryView[string, []string, json.RawMessage](ctx, db.View(design), "conflicts", params)
+	if err != nil {
+		return nil, err
+	}
+	conflicts := make([]ConflictedDoc, len(response.Rows))
+	for i, row := range response.Rows {
+		conflicts[i] = ConflictedDoc{ID: row.ID, Conflicts: row.Value}
+	}
+	return conflicts, nil
+}
+
+// Resolver merges the live leaf revisions of a conflicted document into the
+// document to keep. The leaves are ordered with the revision CouchDB picked
+// as the winner first; their _id and _rev fields are set and their _attachments
+// carry the content inline. Numbers are json.Number.
+type Resolver func(leaves []map[string]interface{}) (map[string]interface{}, error)
+
+// Resolve loads all leaf revisions of document id, merges them with resolve
+// and, in a single _bulk_docs request, stores the result as a new revision of
+// the winning leaf and deletes the other leaves.
+// It does nothing and returns nil if the document has no conflicts.
+func (db *Database) Resolve(ctx context.Context, id string, resolve Resolver) ([]DocumentResponse, error) {
+	// Attachments are loaded inline since stubs of a losing leaf
+	// would be rejected as missing when written on the winning one.
+	revs, err := db.OpenRevs(ctx, id, &GetOptions{Attachments: true})
+	if err != nil {
+		return nil, err
+	}
+	var leaves []map[string]interface{}
+	for _, rev := range revs {
+		if rev.OK == nil {
+			continue
+		}
+		leaf := map[string]interface{}{}
+		dec := json.NewDecoder(bytes.NewReader(rev.OK))
+		dec.UseNumber()
+		if err := dec.Decode(&leaf); err != nil {
+			return nil, err
+		}
+		if deleted, _ := leaf["_deleted"].(bool); !deleted {
+			leaves = append(leaves, leaf)
+		}
+	}
+	if len(leaves) < 2 {
+		return nil, nil
+	}
+	sort.SliceStable(leaves, func(i, j int) bool {
+		return revLess(leafRev(leaves[j]), leafRev(leaves[i]))
+	})
+	// Take the revisions before resolve can modify the leaves.
+	leafRevs := make([]string, len(leaves))
+	for i, leaf := range leaves {
+		leafRevs[i] = leafRev(leaf)
+	}
+	merged, err := resolve(leaves)
+	if err != nil {
+		return nil, err
+	}
+	merged["_id"] = id
+	merged["_rev"] = leafRevs[0]
+	docs := []interface{}{merged}
+	for _, rev := range leafRevs[1:] {
+		docs = append(docs, map[string]interface{}{"_id": id, "_rev": rev, "_deleted": true})
+	}
+	u := fmt.Sprintf("%s/%s/_bulk_docs", db.Host, url.PathEscape(db.Name))
+	bulk := struct {
+		Docs []interface{} `json:"docs"`
+	}{
+		Docs: docs,
+	}
+	response := []DocumentResponse{}
+	if err := db.Client.PostContext(ctx, u, bulk, &response); err != nil {
+		return nil, err
+	}
+	for _, r := range response {
+		if r.Error != "" {
+			err, ok := errorCodes[r.Error]
+			if !ok {
+				err = errors.New(r.Error)
+			}
+			return response, fmt.Errorf("couchdb: resolve %s: %w: %s", id, err, r.Reason)
+		}
+	}
+	return response, nil
+}
+
+func leafRev(doc map[string]interface{}) string {
+	rev, _ := doc["_rev"].(string)
+	return rev
+}
+
+// revLess orders revisions like CouchDB picks the winner: by generation, then by hash.
+func revLess(a, b string) bool {
+	ga, ha := splitRev(a)
+	gb, hb := splitRev(b)
+	if ga != gb {
+		return ga < gb
+	}
+	return ha < hb
+}
+
+func splitRev(rev string) (int, string) {
+	gen, hash, _ := strings.Cut(rev, "-")
+	n, _ := strconv.Atoi(gen)
+	return n, hash
+}
+
+// LastWriteWins returns a Resolver keeping the leaf with the latest value of
+// the timestamp field. Timestamps are numbers or RFC 3339 strings; leaves
+// without the field lose, and ties go to the winning revision.
+func LastWriteWins(field string) Resolver {
+	return func(leaves []map[string]interface{}) (map[string]interface{}, error) {
+		latest := leaves[0]
+		for _, leaf := range leaves[1:] {
+			if timestampAfter(leaf[field], latest[field]) {
+				latest = leaf
+			}
+		}
+		return latest, nil
+	}
+}
+
+func timestampAfter(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		fa, err := a.Float64()
+		if err != nil {
+			return false
+		}
+		if b, ok := b.(json.Number); ok {
+			fb, err := b.Float64()
+			return err != nil || fa > fb
+		}
+		return b == nil
+	case string:
+		bs, ok := b.(string)
+		if !ok {
+			return b == nil
+		}
+		ta, erra := time.Parse(time.RFC3339Nano, a)
+		tb, errb := time.Parse(time.RFC3339Nano, bs)
+		if erra == nil && errb == nil {
+			return ta.After(tb)
+		}
+		return a > bs
+	}
+	return false
+}
+
+// DeepMerge is a Resolver merging the leaves field by field. Objects are merged
+// recursively; for other values, and arrays, the winning revision takes precedence
+// over the following leaves.
+func DeepMerge(leaves []map[string]interface{}) (map[string]interface{}, error) {
+	merged := map[string]interface{}{}
+	for i := len(leaves) - 1; i >= 0; i-- {
+		mergeInto(merged, leaves[i])
+	}
+	return merged, nil
+}
+
+func mergeInto(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sv, ok := v.(map[string]interface{}); ok {
+			if dv, ok := dst[k].(map[string]interface{}); ok {
+				mergeInto(dv, sv)
+				continue
+			}
+			v = copyObject(sv)
+		}
+		dst[k] = v
+	}
+}
+
+func copyObject(src map[string]interface{}) map[string]interface{} {
+	dst := make(map[string]interface{}, len(src))
+	mergeInto(dst, src)
+	return dst
+}
@@ -0,0 +1,150 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// conflictServer serves the leaves of document "a" for open_revs and records the _bulk_docs body.
+func conflictServer(t *testing.T, leaves string, bulk *interface{}) DatabaseService {
+	return testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/db/a":
+			q := r.URL.Query()
+			if q.Get("open_revs") != "all" || q.Get("attachments") != "true" {
+				t.Errorf("query = %s, want open_revs=all with attachments", r.URL.RawQuery)
+			}
+			w.Write([]byte(leaves))
+		case r.Method == "POST" && r.URL.Path == "/db/_bulk_docs":
+			b, _ := ioutil.ReadAll(r.Body)
+			if err := unmarshalNumbers(b, bulk); err != nil {
+				t.Error(err)
+			}
+			w.Write([]byte(`[{"ok":true,"id":"a","rev":"4-n"},{"ok":true,"id":"a","rev":"4-d"}]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+}
+
+// unmarshalNumbers decodes numbers as json.Number, so large ones compare exactly.
+func unmarshalNumbers(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+const conflictLeaves = `[
+	{"ok":{"_id":"a","_rev":"3-b","ts":"2024-01-02T00:00:00Z","n":{"x":1,"big":12345678901234567890},
+		"_attachments":{"b.txt":{"content_type":"text/plain","data":"Yg=="}}}},
+	{"ok":{"_id":"a","_rev":"3-c","ts":"2024-01-01T00:00:00Z","n":{"x":2,"y":2}}},
+	{"ok":{"_id":"a","_rev":"5-z","_deleted":true}},
+	{"missing":"2-m"}
+]`
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		resolve Resolver
+		want    string
+	}{
+		{
+			// 3-b is the latest write, 3-c the revision CouchDB picked; 5-z is deleted
+			name:    "last write wins",
+			resolve: LastWriteWins("ts"),
+			want: `{"docs":[
+				{"_id":"a","_rev":"3-c","ts":"2024-01-02T00:00:00Z","n":{"x":1,"big":12345678901234567890},
+					"_attachments":{"b.txt":{"content_type":"text/plain","data":"Yg=="}}},
+				{"_id":"a","_rev":"3-b","_deleted":true}
+			]}`,
+		},
+		{
+			name:    "deep merge",
+			resolve: DeepMerge,
+			want: `{"docs":[
+				{"_id":"a","_rev":"3-c","ts":"2024-01-01T00:00:00Z","n":{"x":2,"y":2,"big":12345678901234567890},
+					"_attachments":{"b.txt":{"content_type":"text/plain","data":"Yg=="}}},
+				{"_id":"a","_rev":"3-b","_deleted":true}
+			]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bulk interface{}
+			db := conflictServer(t, conflictLeaves, &bulk)
+			resp, err := db.Resolve(context.Background(), "a", tt.resolve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp) != 2 {
+				t.Errorf("%d responses, want 2", len(resp))
+			}
+			var want interface{}
+			if err := unmarshalNumbers([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(bulk, want) {
+				got, _ := json.Marshal(bulk)
+				t.Errorf("_bulk_docs body = %s", got)
+			}
+		})
+	}
+}
+
+func TestResolveNoConflicts(t *testing.T) {
+	var bulk interface{}
+	db := conflictServer(t, `[{"ok":{"_id":"a","_rev":"3-b"}},{"ok":{"_id":"a","_rev":"4-z","_deleted":true}}]`, &bulk)
+	called := false
+	resp, err := db.Resolve(context.Background(), "a", func(leaves []map[string]interface{}) (map[string]interface{}, error) {
+		called = true
+		return leaves[0], nil
+	})
+	if err != nil || resp != nil || called || bulk != nil {
+		t.Errorf("Resolve() = %v, %v, resolver called %v, body %v; want a no-op", resp, err, called, bulk)
+	}
+}
+
+func TestRevLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		less bool
+	}{
+		{"1-b", "2-a", true},
+		{"9-a", "10-a", true},
+		{"3-b", "3-c", true},
+		{"3-c", "3-b", false},
+		{"3-c", "3-c", false},
+	}
+	for _, tt := range tests {
+		if got := revLess(tt.a, tt.b); got != tt.less {
+			t.Errorf("revLess(%s, %s) = %v", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestTimestampAfter(t *testing.T) {
+	tests := []struct {
+		a, b  interface{}
+		after bool
+	}{
+		{json.Number("2"), json.Number("1"), true},
+		{json.Number("1"), json.Number("2"), false},
+		{json.Number("1"), nil, true},
+		{nil, json.Number("1"), false},
+		{"2024-01-01T10:00:00+02:00", "2024-01-01T09:00:00Z", false},
+		{"2024-01-01T10:00:00Z", "2024-01-01T09:59:59.5Z", true},
+		{"b", "a", true},
+		{"a", nil, true},
+		{"a", json.Number("1"), false},
+	}
+	for _, tt := range tests {
+		if got := timestampAfter(tt.a, tt.b); got != tt.after {
+			t.Errorf("timestampAfter(%v, %v) = %v", tt.a, tt.b, got)
+		}
+	}
+}
@@ -1,6 +1,18 @@
 package couchdb
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testDatabase returns the database "db" of a test server answering with h.
+func testDatabase(t *testing.T, h http.HandlerFunc, opts ...Option) DatabaseService {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, "", "", opts...).Use("db")
+}
 
 func TestFind(t *testing.T) {
 
@@ -2,15 +2,13 @@ package couchdb
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"mime/multipart"
 	"net/url"
 	"os"
-	"reflect"
 	"strings"
-
-	"github.com/google/go-querystring/query"
 )
 
 type Database struct {
@@ -22,51 +20,87 @@ type Database struct {
 // AllDesignDocs returns all design documents from database.
 // http://stackoverflow.com/questions/2814352/get-all-design-documents-in-couchdb
 func (db *Database) AllDesignDocs() ([]DesignDocument, error) {
-	startKey := fmt.Sprintf("%q", "_design/")
-	endKey := fmt.Sprintf("%q", "_design0")
+	return db.AllDesignDocsContext(context.Background())
+}
+
+// AllDesignDocsContext is like AllDesignDocs but uses ctx for the request.
+func (db *Database) AllDesignDocsContext(ctx context.Context) ([]DesignDocument, error) {
 	includeDocs := true
 	q := QueryParameters{
-		StartKey:    &startKey,
-		EndKey:      &endKey,
+		StartKey:    "_design/",
+		EndKey:      "_design0",
 		IncludeDocs: &includeDocs,
 	}
-	res, err := db.AllDocs(&q)
+	res, err := AllDocsOf[DesignDocument](ctx, db, &q)
 	if err != nil {
 		return nil, err
 	}
-	docs := make([]interface{}, len(res.Rows))
-	for index, row := range res.Rows {
-		docs[index] = row.Doc
-	}
-	designDocs := make([]DesignDocument, len(docs))
-	b, err := json.Marshal(docs)
-	if err != nil {
-		return nil, err
+	designDocs := make([]DesignDocument, 0, len(res.Rows))
+	for _, row := range res.Rows {
+		if row.Doc != nil {
+			designDocs = append(designDocs, *row.Doc)
+		}
 	}
-	return designDocs, json.Unmarshal(b, &designDocs)
+	return designDocs, nil
 }
 
 // AllDocs returns all documents in selected database.
 // http://docs.couchdb.org/en/latest/api/database/bulk-api.html
 func (db *Database) AllDocs(params *QueryParameters) (*ViewResponse, error) {
-	q, err := query.Values(params)
-	if err != nil {
-		return nil, err
-	}
-	u := fmt.Sprintf("%s/%s/_all_docs?%s", db.Host, url.PathEscape(db.Name), q.Encode())
+	return db.AllDocsContext(context.Background(), params)
+}
+
+// AllDocsContext is like AllDocs but uses ctx for the request.
+func (db *Database) AllDocsContext(ctx context.Context, params *QueryParameters) (*ViewResponse, error) {
 	response := &ViewResponse{}
-	err = db.Client.Get(u, response)
+	err := db.AllDocsInto(ctx, params, response)
 	return response, err
 }
 
+// AllDocsInto is like AllDocsContext but decodes the response into out.
+func (db *Database) AllDocsInto(ctx context.Context, params *QueryParameters, out interface{}) error {
+	base := fmt.Sprintf("%s/%s/_all_docs", db.Host, url.PathEscape(db.Name))
+	method, u, in, err := params.request(base)
+	if err != nil {
+		return err
+	}
+	return db.Client.do(ctx, u, method, in, out)
+}
+
+// docPath escapes a document ID for use in a URL path. The slash after
+// the _design and _local prefixes is kept, as CouchDB expects.
+func docPath(id string) string {
+	for _, prefix := range []string{"_design/", "_local/"} {
+		if strings.HasPrefix(id, prefix) {
+			return prefix + url.PathEscape(strings.TrimPrefix(id, prefix))
+		}
+	}
+	return url.PathEscape(id)
+}
+
 func (db *Database) Get(doc CouchDoc, id string) error {
-	u := fmt.Sprintf("%s/%s/%s", db.Host, url.PathEscape(db.Name), url.PathEscape(id))
-	return db.Client.Get(u, doc)
+	return db.GetContext(context.Background(), doc, id)
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (db *Database) GetContext(ctx context.Context, doc CouchDoc, id string) error {
+	return db.GetInto(ctx, id, doc)
+}
+
+// GetInto is like GetContext but decodes the document into any value out.
+func (db *Database) GetInto(ctx context.Context, id string, out interface{}) error {
+	u := fmt.Sprintf("%s/%s/%s", db.Host, url.PathEscape(db.Name), docPath(id))
+	return db.Client.GetContext(ctx, u, out)
 }
 
 func (db *Database) Rev(id string) (string, error) {
-	u := fmt.Sprintf("%s/%s/%s", db.Host, url.PathEscape(db.Name), url.PathEscape(id))
-	resp, err := db.Client.Head(u)
+	return db.RevContext(context.Background(), id)
+}
+
+// RevContext is like Rev but uses ctx for the request.
+func (db *Database) RevContext(ctx context.Context, id string) (string, error) {
+	u := fmt.Sprintf("%s/%s/%s", db.Host, url.PathEscape(db.Name), docPath(id))
+	resp, err := db.Client.HeadContext(ctx, u)
 	if err != nil {
 		return "", err
 	}
@@ -78,47 +112,72 @@ func (db *Database) Rev(id string) (string, error) {
 }
 
 func (db *Database) Put(doc CouchDoc) (*DocumentResponse, error) {
+	return db.PutContext(context.Background(), doc)
+}
+
+// PutContext is like Put but uses ctx for the request.
+func (db *Database) PutContext(ctx context.Context, doc CouchDoc) (*DocumentResponse, error) {
 	var u string
 	if len(doc.GetRev()) > 0 {
-		u = fmt.Sprintf("%s/%s/%s?rev=%s", db.Host, url.PathEscape(db.Name), url.PathEscape(doc.GetID()), doc.GetRev())
+		u = fmt.Sprintf("%s/%s/%s?rev=%s", db.Host, url.PathEscape(db.Name), docPath(doc.GetID()), doc.GetRev())
 	} else {
-		u = fmt.Sprintf("%s/%s/%s", db.Host, url.PathEscape(db.Name), url.PathEscape(doc.GetID()))
+		u = fmt.Sprintf("%s/%s/%s", db.Host, url.PathEscape(db.Name), docPath(doc.GetID()))
 	}
 	response := &DocumentResponse{}
-	err := db.Client.Put(u, doc, response)
+	err := db.Client.PutContext(ctx, u, doc, response)
 	return response, err
 }
 
 func (db *Database) Post(doc CouchDoc) (*DocumentResponse, error) {
+	return db.PostContext(context.Background(), doc)
+}
+
+// PostContext is like Post but uses ctx for the request.
+func (db *Database) PostContext(ctx context.Context, doc CouchDoc) (*DocumentResponse, error) {
 	u := fmt.Sprintf("%s/%s", db.Host, url.PathEscape(db.Name))
 	response := &DocumentResponse{}
-	err := db.Client.Post(u, doc, response)
+	err := db.Client.PostContext(ctx, u, doc, response)
 	return response, err
 }
 
 func (db *Database) Delete(doc CouchDoc) (*DocumentResponse, error) {
-	u := fmt.Sprintf("%s/%s/%s?rev=%s", db.Host, url.PathEscape(db.Name), url.PathEscape(doc.GetID()), doc.GetRev())
+	return db.DeleteContext(context.Background(), doc)
+}
+
+// DeleteContext is like Delete but uses ctx for the request.
+func (db *Database) DeleteContext(ctx context.Context, doc CouchDoc) (*DocumentResponse, error) {
+	u := fmt.Sprintf("%s/%s/%s?rev=%s", db.Host, url.PathEscape(db.Name), docPath(doc.GetID()), doc.GetRev())
 	response := &DocumentResponse{}
-	err := db.Client.Delete(u, response)
+	err := db.Client.DeleteContext(ctx, u, response)
 	return response, err
 }
 
 func (db *Database) Store(doc CouchDoc) (*DocumentResponse, error) {
-	rev, err := db.Rev(doc.GetID())
+	return db.StoreContext(context.Background(), doc)
+}
+
+// StoreContext is like Store but uses ctx for the request.
+func (db *Database) StoreContext(ctx context.Context, doc CouchDoc) (*DocumentResponse, error) {
+	rev, err := db.RevContext(ctx, doc.GetID())
 	if err == nil {
 		doc.SetRev(rev)
 		//覆盖修改
-		return db.Put(doc)
+		return db.PutContext(ctx, doc)
 	}
-	return db.Post(doc)
+	return db.PostContext(ctx, doc)
 }
 
 func (db *Database) MultiStore(docs []CouchDoc) (error, []string, []string) {
+	return db.MultiStoreContext(context.Background(), docs)
+}
+
+// MultiStoreContext is like MultiStore but uses ctx for the request.
+func (db *Database) MultiStoreContext(ctx context.Context, docs []CouchDoc) (error, []string, []string) {
 	docsMap := make(map[string]CouchDoc)
 	for _, v := range docs {
 		docsMap[v.GetID()] = v
 	}
-	resp, err := db.Bulk(docs)
+	resp, err := db.BulkContext(ctx, docs)
 	if err != nil {
 		return err, nil, nil
 	}
@@ -132,7 +191,7 @@ func (db *Database) MultiStore(docs []CouchDoc) (error, []string, []string) {
 		if v.Error == "conflict" {
 			//update
 			if vv, ok := docsMap[v.ID]; ok {
-				if rev, err := db.Rev(v.ID); err == nil {
+				if rev, err := db.RevContext(ctx, v.ID); err == nil {
 					vv.SetRev(rev)
 					rebulkDocs = append(rebulkDocs, vv)
 				}
@@ -141,7 +200,7 @@ func (db *Database) MultiStore(docs []CouchDoc) (error, []string, []string) {
 		}
 	}
 	if len(rebulkDocs) > 0 {
-		_, err = db.Bulk(rebulkDocs)
+		_, err = db.BulkContext(ctx, rebulkDocs)
 	}
 
 	return err, news, updates
@@ -149,6 +208,11 @@ func (db *Database) MultiStore(docs []CouchDoc) (error, []string, []string) {
 
 // PutAttachment adds attachment to document
 func (db *Database) PutAttachmentToDoc(doc CouchDoc, path string) (*DocumentResponse, error) {
+	return db.PutAttachmentToDocContext(context.Background(), doc, path)
+}
+
+// PutAttachmentToDocContext is like PutAttachmentToDoc but uses ctx for the request.
+func (db *Database) PutAttachmentToDocContext(ctx context.Context, doc CouchDoc, path string) (*DocumentResponse, error) {
 	// get file from disk
 	file, err := os.Open(path)
 	if err != nil {
@@ -183,10 +247,10 @@ func (db *Database) PutAttachmentToDoc(doc CouchDoc, path string) (*DocumentResp
 	}
 
 	// create http request
-	u := fmt.Sprintf("%s/%s/%s", db.Host, url.PathEscape(db.Name), url.PathEscape(doc.GetID()))
+	u := fmt.Sprintf("%s/%s/%s", db.Host, url.PathEscape(db.Name), docPath(doc.GetID()))
 	contentType := fmt.Sprintf("multipart/related; boundary=%q", writer.Boundary())
 	response := &DocumentResponse{}
-	err = db.Client.PutWithData(u, &buffer, response, contentType)
+	err = db.Client.PutWithDataContext(ctx, u, &buffer, response, contentType)
 	return response, err
 }
 
@@ -195,21 +259,31 @@ func (db *Database) PutAttachmentToDoc(doc CouchDoc, path string) (*DocumentResp
 // creating or updating a single document, except that you batch
 // the document structure and information.
 func (db *Database) Bulk(docs []CouchDoc) ([]DocumentResponse, error) {
+	return db.BulkContext(context.Background(), docs)
+}
+
+// BulkContext is like Bulk but uses ctx for the request.
+func (db *Database) BulkContext(ctx context.Context, docs []CouchDoc) ([]DocumentResponse, error) {
 	bulk := BulkDoc{
 		Docs: docs,
 	}
 	u := fmt.Sprintf("%s/%s/_bulk_docs", db.Host, url.PathEscape(db.Name))
 	response := []DocumentResponse{}
-	err := db.Client.Post(u, bulk, &response)
+	err := db.Client.PostContext(ctx, u, bulk, &response)
 	return response, err
 }
 
 // Purge permanently removes the references to deleted documents from the database.
 // http://docs.couchdb.org/en/1.6.1/api/database/misc.html
 func (db *Database) Purge(req map[string][]string) (*PurgeResponse, error) {
+	return db.PurgeContext(context.Background(), req)
+}
+
+// PurgeContext is like Purge but uses ctx for the request.
+func (db *Database) PurgeContext(ctx context.Context, req map[string][]string) (*PurgeResponse, error) {
 	u := fmt.Sprintf("%s/%s/_purge", db.Host, url.PathEscape(db.Name))
 	response := &PurgeResponse{}
-	err := db.Client.Post(u, req, response)
+	err := db.Client.PostContext(ctx, u, req, response)
 	return response, err
 }
 
@@ -223,15 +297,20 @@ func (db *Database) View(name string) ViewService {
 
 // Seed makes sure all your design documents are up to date.
 func (db *Database) Seed(cache []DesignDocument) error {
+	return db.SeedContext(context.Background(), cache)
+}
+
+// SeedContext is like Seed but uses ctx for the request.
+func (db *Database) SeedContext(ctx context.Context, cache []DesignDocument) error {
 	// query all docs to get all design documents
-	designDocs, err := db.AllDesignDocs()
+	designDocs, err := db.AllDesignDocsContext(ctx)
 	if err != nil {
 		return err
 	}
 	difference := diff(cache, designDocs)
 	// remove all deletions
 	for _, doc := range difference.deletions {
-		if _, err := db.Delete(&doc); err != nil {
+		if _, err := db.DeleteContext(ctx, &doc); err != nil {
 			return err
 		}
 	}
@@ -239,18 +318,18 @@ func (db *Database) Seed(cache []DesignDocument) error {
 	for _, doc := range difference.changes {
 		// get design document first to get current revision
 		var old DesignDocument
-		if err := db.Get(&old, doc.ID); err != nil {
+		if err := db.GetContext(ctx, &old, doc.ID); err != nil {
 			return err
 		}
 		// update document with new version
 		doc.Rev = old.Rev
-		if _, err := db.Put(&doc); err != nil {
+		if _, err := db.PutContext(ctx, &doc); err != nil {
 			return err
 		}
 	}
 	// add all additions
 	for _, doc := range difference.additions {
-		if _, err := db.Put(&doc); err != nil {
+		if _, err := db.PutContext(ctx, &doc); err != nil {
 			return err
 		}
 	}
@@ -277,9 +356,9 @@ func diff(cache, db []DesignDocument) difference {
 		for _, d := range db {
 			if d.ID == c.ID {
 				exists = true
-				// check for different map/reduce and language
+				// check for different views, filters and search indexes
 				// do not check for different revision
-				if !reflect.DeepEqual(c.Views, d.Views) {
+				if !sameDesign(c, d) {
 					existsButDifferent = true
 				}
 			}
@@ -307,14 +386,36 @@ func diff(cache, db []DesignDocument) difference {
 	return di
 }
 
+// sameDesign reports whether a and b declare the same views, filters and search indexes.
+// They are compared in their JSON form, so e.g. an analyzer given as a
+// map[string]string equals the map[string]interface{} read from the database.
+func sameDesign(a, b DesignDocument) bool {
+	declarations := func(dd DesignDocument) []byte {
+		dd.Document, dd.Language = Document{}, ""
+		data, _ := json.Marshal(dd)
+		return data
+	}
+	return bytes.Equal(declarations(a), declarations(b))
+}
+
 func (db *Database) Find(args *FindArgs, out interface{}) error {
+	return db.FindContext(context.Background(), args, out)
+}
+
+// FindContext is like Find but uses ctx for the request.
+func (db *Database) FindContext(ctx context.Context, args *FindArgs, out interface{}) error {
 	u := fmt.Sprintf("%s/%s/_find", db.Host, url.PathEscape(db.Name))
-	return db.Client.Post(u, args, out)
+	return db.Client.PostContext(ctx, u, args, out)
 }
 
 func (db *Database) CreateIndex(args *Index) (*CouchIndexBody, error) {
+	return db.CreateIndexContext(context.Background(), args)
+}
+
+// CreateIndexContext is like CreateIndex but uses ctx for the request.
+func (db *Database) CreateIndexContext(ctx context.Context, args *Index) (*CouchIndexBody, error) {
 	u := fmt.Sprintf("%s/%s/_index", db.Host, url.PathEscape(db.Name))
 	response := &CouchIndexBody{}
-	err := db.Client.Post(u, args, response)
+	err := db.Client.PostContext(ctx, u, args, response)
 	return response, err
 }
@@ -2,10 +2,42 @@ package couchdb
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 )
 
+// Sentinel errors for the common CouchDB error codes. A returned *Error
+// matches them with errors.Is, e.g. errors.Is(err, ErrNotFound).
+var (
+	ErrBadRequest   = errors.New("couchdb: bad_request")
+	ErrUnauthorized = errors.New("couchdb: unauthorized")
+	ErrForbidden    = errors.New("couchdb: forbidden")
+	ErrNotFound     = errors.New("couchdb: not_found")
+	ErrConflict     = errors.New("couchdb: conflict")
+	ErrFileExists   = errors.New("couchdb: file_exists")
+)
+
+var errorCodes = map[string]error{
+	"bad_request":  ErrBadRequest,
+	"unauthorized": ErrUnauthorized,
+	"forbidden":    ErrForbidden,
+	"not_found":    ErrNotFound,
+	"conflict":     ErrConflict,
+	"file_exists":  ErrFileExists,
+}
+
+// errorStatuses is used when the response carries no error code, as is the case for HEAD requests.
+var errorStatuses = map[int]error{
+	http.StatusBadRequest:         ErrBadRequest,
+	http.StatusUnauthorized:       ErrUnauthorized,
+	http.StatusForbidden:          ErrForbidden,
+	http.StatusNotFound:           ErrNotFound,
+	http.StatusConflict:           ErrConflict,
+	http.StatusPreconditionFailed: ErrFileExists,
+}
+
 // Error represents API-level errors, reported by CouchDB as
 //
 //	{"error": <ErrorCode>, "reason": <Reason>}
@@ -27,6 +59,15 @@ func (e *Error) Error() string {
 		e.Method, e.URL, e.StatusCode, e.ErrorCode, e.Reason)
 }
 
+// Is reports whether the error matches one of the sentinel errors,
+// comparing the CouchDB error code or, if it is empty, the status code.
+func (e *Error) Is(target error) bool {
+	if e.ErrorCode != "" {
+		return errorCodes[e.ErrorCode] == target
+	}
+	return errorStatuses[e.StatusCode] == target
+}
+
 // NotFound checks whether the given errors is a DatabaseError
 // with StatusCode == 404. This is useful for conditional creation
 // of databases and documents.
@@ -49,15 +90,22 @@ func Conflict(err error) bool {
 // ErrorStatus checks whether the given error is a DatabaseError
 // with a matching statusCode.
 func ErrorStatus(err error, statusCode int) bool {
-	dberr, ok := err.(*Error)
-	return ok && dberr.StatusCode == statusCode
+	var dberr *Error
+	return errors.As(err, &dberr) && dberr.StatusCode == statusCode
 }
 
+// parseError turns a non-2xx response into an *Error and closes its body.
+// A body that is not a CouchDB error object ends up verbatim in Reason.
 func parseError(req *http.Request, resp *http.Response) error {
+	defer resp.Body.Close()
 	var reply struct{ Error, Reason string }
 	if req.Method != "HEAD" {
-		if err := readBody(resp, &reply); err != nil {
-			return fmt.Errorf("couldn't decode CouchDB error: %v", err)
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("couldn't read CouchDB error: %w", err)
+		}
+		if err := json.Unmarshal(b, &reply); err != nil {
+			reply.Reason = string(b)
 		}
 	}
 	return &Error{
@@ -0,0 +1,92 @@
+package couchdb
+
+import "context"
+
+// defaultFindLimit is the limit CouchDB applies to _find when none is given.
+const defaultFindLimit = 25
+
+// FindIterator pages through the results of a Mango query with bookmarks.
+// Each call to Next issues one _find request with the bookmark returned
+// by the previous one, until a page has fewer documents than the limit.
+//
+//	it := couchdb.NewFindIterator[User](db, couchdb.FindArgs{Selector: selector, Limit: 100})
+//	for it.Next(ctx) {
+//		for _, user := range it.Docs() {
+//		}
+//	}
+//	return it.Err()
+type FindIterator[T any] struct {
+	db       DatabaseService
+	args     FindArgs
+	docs     []T
+	warnings []string
+	stats    ExecutionStats
+	err      error
+	done     bool
+}
+
+// NewFindIterator returns a FindIterator for args. A Bookmark in args resumes a previous iteration.
+func NewFindIterator[T any](db DatabaseService, args FindArgs) *FindIterator[T] {
+	return &FindIterator[T]{db: db, args: args}
+}
+
+// Next fetches the next page. It returns false when there are no more documents or on error.
+func (it *FindIterator[T]) Next(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+	res, err := FindDocs[T](ctx, it.db, &it.args)
+	if err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+	if res.Warning != "" && !containsString(it.warnings, res.Warning) {
+		it.warnings = append(it.warnings, res.Warning)
+	}
+	it.addStats(res.ExecutionStats)
+	limit := it.args.Limit
+	if limit <= 0 {
+		limit = defaultFindLimit
+	}
+	it.docs = res.Docs
+	it.args.Bookmark = res.BookMark
+	// The bookmark already accounts for Skip; CouchDB would apply it again on every page.
+	it.args.Skip = 0
+	it.done = int64(len(res.Docs)) < limit || res.BookMark == ""
+	return len(res.Docs) > 0
+}
+
+func (it *FindIterator[T]) addStats(s ExecutionStats) {
+	it.stats.ExecutionTimeMs += s.ExecutionTimeMs
+	it.stats.ResultsReturned += s.ResultsReturned
+	it.stats.TotalDocsExamined += s.TotalDocsExamined
+	it.stats.TotalKeysExamined += s.TotalKeysExamined
+	it.stats.TotalQuorumDocsExamined += s.TotalQuorumDocsExamined
+}
+
+// Docs returns the documents of the current page.
+func (it *FindIterator[T]) Docs() []T {
+	return it.docs
+}
+
+// Bookmark returns the bookmark to resume after the current page.
+func (it *FindIterator[T]) Bookmark() string {
+	return it.args.Bookmark
+}
+
+// Warnings returns the distinct warnings reported so far.
+func (it *FindIterator[T]) Warnings() []string {
+	return it.warnings
+}
+
+// ExecutionStats returns the execution statistics summed over all pages.
+// They are only reported if FindArgs.ExecutionStats is set.
+func (it *FindIterator[T]) ExecutionStats() ExecutionStats {
+	return it.stats
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *FindIterator[T]) Err() error {
+	return it.err
+}
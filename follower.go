@@ -0,0 +1,212 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Follower consumes the changes feed of a database with at-least-once semantics.
+// Changes are passed to Handler in batches and the sequence of a batch is
+// saved to Store only after Handler returned successfully. After network
+// errors Follower reconnects and resumes from the last saved sequence, so a
+// batch may be delivered again after a failure or restart.
+type Follower struct {
+	DB      DatabaseService
+	Store   CheckpointStore
+	Handler func(ctx context.Context, batch []Change) error
+
+	// Options of the changes feed. Since is taken from Store and Feed
+	// defaults to FeedContinuous; FeedLongpoll may be used as well,
+	// while FeedNormal and FeedEventSource are rejected.
+	Options ChangesOptions
+
+	BatchSize     int           // Maximum number of changes per batch, defaults to 100
+	FlushInterval time.Duration // Maximum time a change waits for its batch to fill up, defaults to 1s
+	MinRetryDelay time.Duration // Delay before the first reconnect, defaults to 1s
+	MaxRetryDelay time.Duration // Upper bound of the doubling reconnect delay, defaults to 1m
+}
+
+// Run follows the changes feed until ctx is done or Handler or Store fail.
+// Errors which cannot be fixed by reconnecting, such as 4xx responses, end Run as well.
+// On cancellation the batch being handled is completed and Run returns ctx.Err().
+func (f *Follower) Run(ctx context.Context) error {
+	if f.DB == nil || f.Store == nil || f.Handler == nil {
+		return errors.New("couchdb: Follower needs DB, Store and Handler")
+	}
+	switch f.Options.Feed {
+	case "", FeedContinuous, FeedLongpoll:
+	default:
+		return fmt.Errorf("couchdb: Follower does not support feed %q", f.Options.Feed)
+	}
+	since, err := f.Store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	delay := durationOr(f.MinRetryDelay, time.Second)
+	for {
+		var feedErr, err error
+		since, feedErr, err = f.follow(ctx, since)
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if feedErr == nil {
+			delay = durationOr(f.MinRetryDelay, time.Second)
+			continue
+		}
+		if !transient(feedErr) {
+			return feedErr
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if delay *= 2; delay > durationOr(f.MaxRetryDelay, time.Minute) {
+			delay = durationOr(f.MaxRetryDelay, time.Minute)
+		}
+	}
+}
+
+type feedEnd struct {
+	lastSeq Seq
+	err     error
+}
+
+// follow reads one connection of the changes feed starting after since. It returns
+// the last saved sequence, the error that ended the feed and any Handler or Store error.
+func (f *Follower) follow(ctx context.Context, since Seq) (Seq, error, error) {
+	fctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opts := f.Options
+	opts.Since = since
+	if opts.Feed == "" {
+		opts.Feed = FeedContinuous
+	}
+	changes, err := f.DB.Changes(fctx, &opts)
+	if err != nil {
+		return since, err, nil
+	}
+	events := make(chan Change)
+	end := make(chan feedEnd, 1)
+	go func() {
+		defer close(events)
+		defer changes.Close()
+		for changes.Next() {
+			select {
+			case events <- *changes.Change():
+			case <-fctx.Done():
+				end <- feedEnd{err: fctx.Err()}
+				return
+			}
+		}
+		end <- feedEnd{lastSeq: changes.LastSeq(), err: changes.Err()}
+	}()
+
+	size := f.BatchSize
+	if size <= 0 {
+		size = 100
+	}
+	var batch []Change
+	var flushC <-chan time.Time
+	var timer *time.Timer
+	flush := func() error {
+		if timer != nil {
+			timer.Stop()
+			timer, flushC = nil, nil
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := f.Handler(ctx, batch); err != nil {
+			return err
+		}
+		seq := since
+		for _, change := range batch {
+			if change.Seq != "" {
+				seq = change.Seq
+			}
+		}
+		batch = nil
+		if err := f.save(ctx, seq); err != nil {
+			return err
+		}
+		since = seq
+		return nil
+	}
+	for {
+		select {
+		case change, ok := <-events:
+			if !ok {
+				if err := flush(); err != nil {
+					return since, nil, err
+				}
+				e := <-end
+				if e.err == nil && e.lastSeq != "" && e.lastSeq != since {
+					// everything up to last_seq was delivered, which matters for filtered feeds
+					if err := f.save(ctx, e.lastSeq); err != nil {
+						return since, nil, err
+					}
+					since = e.lastSeq
+				}
+				return since, e.err, nil
+			}
+			batch = append(batch, change)
+			if len(batch) >= size {
+				if err := flush(); err != nil {
+					return since, nil, err
+				}
+			} else if timer == nil {
+				timer = time.NewTimer(durationOr(f.FlushInterval, time.Second))
+				flushC = timer.C
+			}
+		case <-flushC:
+			timer, flushC = nil, nil
+			if err := flush(); err != nil {
+				return since, nil, err
+			}
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return since, ctx.Err(), nil
+		}
+	}
+}
+
+// saveTimeout bounds saving a checkpoint after ctx was cancelled.
+const saveTimeout = 10 * time.Second
+
+// save saves seq to Store. If ctx was cancelled while the batch was handled,
+// it saves with a fresh context so the completed batch is not delivered again.
+func (f *Follower) save(ctx context.Context, seq Seq) error {
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), saveTimeout)
+		defer cancel()
+	}
+	return f.Store.Save(ctx, seq)
+}
+
+// transient reports whether reconnecting may help after err.
+func transient(err error) bool {
+	var dberr *Error
+	if !errors.As(err, &dberr) {
+		return true
+	}
+	return dberr.StatusCode >= 500 || dberr.StatusCode == http.StatusTooManyRequests || dberr.StatusCode == http.StatusRequestTimeout
+}
+
+func durationOr(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
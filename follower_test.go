@@ -0,0 +1,253 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// changesFeed answers the n-th _changes request (counting from 0) with feeds[n].
+type changesFeed struct {
+	mu    sync.Mutex
+	since []string
+	feeds []func(w http.ResponseWriter, r *http.Request)
+}
+
+func (f *changesFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	n := len(f.since)
+	f.since = append(f.since, r.URL.Query().Get("since"))
+	f.mu.Unlock()
+	if n >= len(f.feeds) {
+		<-r.Context().Done()
+		return
+	}
+	f.feeds[n](w, r)
+}
+
+func (f *changesFeed) sinces() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.since...)
+}
+
+// stream writes changes with the given sequences and keeps the connection open.
+func stream(seqs ...string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, seq := range seqs {
+			fmt.Fprintf(w, "{\"seq\":%q,\"id\":\"doc-%s\"}\n", seq, seq)
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+}
+
+func status(code int) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"error":%q,"reason":"test"}`, http.StatusText(code))
+	}
+}
+
+// collector is a Follower Handler cancelling ctx once want changes were handled.
+type collector struct {
+	mu     sync.Mutex
+	ids    []string
+	want   int
+	cancel context.CancelFunc
+}
+
+func (c *collector) handle(ctx context.Context, batch []Change) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, change := range batch {
+		c.ids = append(c.ids, change.ID)
+	}
+	if len(c.ids) >= c.want {
+		c.cancel()
+	}
+	return nil
+}
+
+func runFollower(t *testing.T, f *Follower) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- f.Run(context.Background()) }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+		return nil
+	}
+}
+
+func newFollower(t *testing.T, feed *changesFeed, store CheckpointStore) *Follower {
+	return &Follower{
+		DB:            testDatabase(t, feed.ServeHTTP),
+		Store:         store,
+		BatchSize:     1,
+		FlushInterval: 10 * time.Millisecond,
+		MinRetryDelay: 10 * time.Millisecond,
+		MaxRetryDelay: 20 * time.Millisecond,
+	}
+}
+
+func TestFollowerResume(t *testing.T) {
+	feed := &changesFeed{feeds: []func(http.ResponseWriter, *http.Request){stream("6-f", "7-g")}}
+	store := &MemoryCheckpointStore{seq: "5-e"}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &collector{want: 2, cancel: cancel}
+	f := newFollower(t, feed, store)
+	f.Handler = c.handle
+	if err := f.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() = %v, want context.Canceled", err)
+	}
+	if since := feed.sinces(); since[0] != "5-e" {
+		t.Errorf("since = %q, want the stored checkpoint 5-e", since[0])
+	}
+	if seq, _ := store.Load(ctx); seq != "7-g" {
+		t.Errorf("checkpoint = %q, want 7-g saved after cancellation", seq)
+	}
+}
+
+func TestFollowerReconnect(t *testing.T) {
+	feed := &changesFeed{feeds: []func(http.ResponseWriter, *http.Request){
+		status(http.StatusServiceUnavailable),
+		func(w http.ResponseWriter, r *http.Request) {
+			// the connection drops in the middle of the second change
+			w.Write([]byte("{\"seq\":\"1-a\",\"id\":\"a\"}\n{\"seq\":"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+			panic(http.ErrAbortHandler)
+		},
+		stream("2-b"),
+	}}
+	store := &MemoryCheckpointStore{}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &collector{want: 2, cancel: cancel}
+	f := newFollower(t, feed, store)
+	f.Handler = c.handle
+	if err := f.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() = %v, want context.Canceled", err)
+	}
+	since := feed.sinces()
+	if len(since) != 3 || since[0] != "" || since[1] != "" || since[2] != "1-a" {
+		t.Errorf("since of the connections = %q, want [\"\" \"\" \"1-a\"]", since)
+	}
+	if fmt.Sprint(c.ids) != "[a doc-2-b]" {
+		t.Errorf("handled %v", c.ids)
+	}
+}
+
+func TestFollowerHandlerError(t *testing.T) {
+	feed := &changesFeed{feeds: []func(http.ResponseWriter, *http.Request){stream("1-a", "2-b")}}
+	store := &MemoryCheckpointStore{seq: "0-x"}
+	errHandler := errors.New("handler failed")
+	f := newFollower(t, feed, store)
+	f.Handler = func(ctx context.Context, batch []Change) error { return errHandler }
+	if err := runFollower(t, f); err != errHandler {
+		t.Fatalf("Run() = %v, want the handler error", err)
+	}
+	if seq, _ := store.Load(context.Background()); seq != "0-x" {
+		t.Errorf("checkpoint = %q, want it unchanged", seq)
+	}
+}
+
+func TestFollowerPermanentError(t *testing.T) {
+	feed := &changesFeed{feeds: []func(http.ResponseWriter, *http.Request){status(http.StatusNotFound)}}
+	f := newFollower(t, feed, &MemoryCheckpointStore{})
+	f.Handler = func(ctx context.Context, batch []Change) error { return nil }
+	if err := runFollower(t, f); !NotFound(err) {
+		t.Fatalf("Run() = %v, want not found", err)
+	}
+	if n := len(feed.sinces()); n != 1 {
+		t.Errorf("%d connections, want 1", n)
+	}
+}
+
+func TestFollowerInvalid(t *testing.T) {
+	feed := &changesFeed{}
+	handler := func(ctx context.Context, batch []Change) error { return nil }
+	tests := map[string]*Follower{
+		"no store":   {DB: testDatabase(t, feed.ServeHTTP), Handler: handler},
+		"no handler": {DB: testDatabase(t, feed.ServeHTTP), Store: &MemoryCheckpointStore{}},
+		"normal feed": {DB: testDatabase(t, feed.ServeHTTP), Store: &MemoryCheckpointStore{}, Handler: handler,
+			Options: ChangesOptions{Feed: FeedNormal}},
+	}
+	for name, f := range tests {
+		if err := runFollower(t, f); err == nil {
+			t.Errorf("%s: Run() = nil", name)
+		}
+	}
+	if n := len(feed.sinces()); n != 0 {
+		t.Errorf("%d connections, want none", n)
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	s := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoint")}
+	if seq, err := s.Load(ctx); err != nil || seq != "" {
+		t.Fatalf("Load() = %q, %v before Save", seq, err)
+	}
+	for _, want := range []Seq{"1-a", "12-g1AAAA"} {
+		if err := s.Save(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+		if seq, err := s.Load(ctx); err != nil || seq != want {
+			t.Errorf("Load() = %q, %v, want %q", seq, err, want)
+		}
+	}
+}
+
+func TestLocalCheckpointStore(t *testing.T) {
+	var mu sync.Mutex
+	var stored map[string]interface{}
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path != "/db/_local/follower" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		switch r.Method {
+		case "GET":
+			if stored == nil {
+				status(http.StatusNotFound)(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(stored)
+		case "PUT":
+			b, _ := ioutil.ReadAll(r.Body)
+			doc := map[string]interface{}{}
+			json.Unmarshal(b, &doc)
+			if stored != nil && doc["_rev"] != stored["_rev"] {
+				status(http.StatusConflict)(w, r)
+				return
+			}
+			doc["_rev"] = fmt.Sprintf("0-%d", len(b))
+			stored = doc
+			fmt.Fprintf(w, `{"ok":true,"id":"_local/follower","rev":%q}`, doc["_rev"])
+		}
+	})
+	ctx := context.Background()
+	s := &LocalCheckpointStore{DB: db, ID: "follower"}
+	if seq, err := s.Load(ctx); err != nil || seq != "" {
+		t.Fatalf("Load() = %q, %v before Save", seq, err)
+	}
+	for _, want := range []Seq{"1-a", "2-b"} {
+		if err := s.Save(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+		if seq, err := s.Load(ctx); err != nil || seq != want {
+			t.Errorf("Load() = %q, %v, want %q", seq, err, want)
+		}
+	}
+}
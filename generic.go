@@ -0,0 +1,70 @@
+package couchdb
+
+import "context"
+
+// RowOf is a view row with typed key, value and included document.
+// Doc is nil unless include_docs was requested and the document exists.
+type RowOf[K, V, D any] struct {
+	ID    string `json:"id"`
+	Key   K      `json:"key"`
+	Value V      `json:"value"`
+	Doc   *D     `json:"doc,omitempty"`
+	Error string `json:"error,omitempty"` // Set for keys of _all_docs that do not exist
+}
+
+// ViewResponseOf is a view response with typed rows.
+type ViewResponseOf[K, V, D any] struct {
+	Offset    int              `json:"offset,omitempty"`
+	TotalRows int              `json:"total_rows,omitempty"`
+	UpdateSeq Seq              `json:"update_seq,omitempty"`
+	Rows      []RowOf[K, V, D] `json:"rows"`
+}
+
+// AllDocsValue is the value of a row of _all_docs.
+type AllDocsValue struct {
+	Rev     string `json:"rev"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// FindResult is a _find response with typed documents.
+type FindResult[T any] struct {
+	CouchSelectorBody
+	Docs []T `json:"docs"`
+}
+
+// GetDoc returns the document id decoded into a T.
+func GetDoc[T any](ctx context.Context, db DatabaseService, id string) (*T, error) {
+	doc := new(T)
+	if err := db.GetInto(ctx, id, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// FindDocs runs a Mango query and decodes the matching documents into a []T.
+func FindDocs[T any](ctx context.Context, db DatabaseService, args *FindArgs) (*FindResult[T], error) {
+	response := &FindResult[T]{}
+	if err := db.FindContext(ctx, args, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// AllDocsOf queries _all_docs and decodes documents included with IncludeDocs into T.
+func AllDocsOf[T any](ctx context.Context, db DatabaseService, params *QueryParameters) (*ViewResponseOf[string, AllDocsValue, T], error) {
+	response := &ViewResponseOf[string, AllDocsValue, T]{}
+	if err := db.AllDocsInto(ctx, params, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// QueryView queries the view name and decodes keys into K, values into V
+// and documents included with IncludeDocs into D.
+func QueryView[K, V, D any](ctx context.Context, v ViewService, name string, params QueryParameters) (*ViewResponseOf[K, V, D], error) {
+	response := &ViewResponseOf[K, V, D]{}
+	if err := v.GetInto(ctx, name, params, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,97 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// IndexInfo describes an index as listed by GET /{db}/_index and chosen by _explain.
+type IndexInfo struct {
+	DDoc        string   `json:"ddoc"` // Empty for the special _all_docs index
+	Name        string   `json:"name"`
+	Type        string   `json:"type"` // "special", "json" or "text"
+	Partitioned bool     `json:"partitioned,omitempty"`
+	Def         IndexDef `json:"def"`
+}
+
+// IndexDef is the definition of an index.
+type IndexDef struct {
+	Fields                []map[string]string    `json:"fields"` // Field name to sort order
+	PartialFilterSelector map[string]interface{} `json:"partial_filter_selector,omitempty"`
+}
+
+// IndexList is the response of GET /{db}/_index.
+type IndexList struct {
+	TotalRows int         `json:"total_rows"`
+	Indexes   []IndexInfo `json:"indexes"`
+}
+
+// ExplainResult is the response of POST /{db}/_explain.
+// http://docs.couchdb.org/en/stable/api/database/find.html#db-explain
+type ExplainResult struct {
+	DBName   string                 `json:"dbname"`
+	Index    IndexInfo              `json:"index"`
+	Selector map[string]interface{} `json:"selector"`
+	Opts     map[string]interface{} `json:"opts"`
+	Limit    int                    `json:"limit"`
+	Skip     int                    `json:"skip"`
+	Fields   json.RawMessage        `json:"fields"` // "all_fields" or an array of field names
+	Range    ExplainRange           `json:"mrargs"`
+	Covering *bool                  `json:"covering,omitempty"` // Whether the index answers the query without reading documents, reported by CouchDB 3.4 and later
+
+	IndexCandidates []IndexCandidate `json:"index_candidates,omitempty"` // Indexes considered but not chosen, reported by CouchDB 3.4 and later
+}
+
+// ExplainRange holds the key range and view options the query runs with on the chosen index.
+type ExplainRange struct {
+	StartKey    json.RawMessage `json:"start_key"`
+	EndKey      json.RawMessage `json:"end_key"`
+	Direction   string          `json:"direction"`
+	IncludeDocs bool            `json:"include_docs"`
+	Reduce      bool            `json:"reduce"`
+	Stable      bool            `json:"stable"`
+	Update      json.RawMessage `json:"update"`
+	Conflicts   json.RawMessage `json:"conflicts"`
+}
+
+// IndexCandidate is an index _explain considered and the reasons it was not used.
+type IndexCandidate struct {
+	Index    IndexInfo `json:"index"`
+	Analysis struct {
+		Usable  bool `json:"usable"`
+		Reasons []struct {
+			Name string `json:"name"`
+		} `json:"reasons"`
+		Ranking  int  `json:"ranking"`
+		Covering bool `json:"covering"`
+	} `json:"analysis"`
+}
+
+// ListIndexes returns all Mango indexes of the database.
+// http://docs.couchdb.org/en/stable/api/database/find.html#get--db-_index
+func (db *Database) ListIndexes(ctx context.Context) (*IndexList, error) {
+	u := fmt.Sprintf("%s/%s/_index", db.Host, url.PathEscape(db.Name))
+	response := &IndexList{}
+	err := db.Client.GetContext(ctx, u, response)
+	return response, err
+}
+
+// DeleteIndex deletes the json index name from the design document ddoc,
+// which may be given with or without the "_design/" prefix.
+// http://docs.couchdb.org/en/stable/api/database/find.html#delete--db-_index-designdoc-json-name
+func (db *Database) DeleteIndex(ctx context.Context, ddoc, name string) error {
+	ddoc = strings.TrimPrefix(ddoc, "_design/")
+	u := fmt.Sprintf("%s/%s/_index/_design/%s/json/%s", db.Host, url.PathEscape(db.Name), url.PathEscape(ddoc), url.PathEscape(name))
+	return db.Client.DeleteContext(ctx, u, nil)
+}
+
+// Explain returns which index Find would use for args and how.
+func (db *Database) Explain(ctx context.Context, args *FindArgs) (*ExplainResult, error) {
+	u := fmt.Sprintf("%s/%s/_explain", db.Host, url.PathEscape(db.Name))
+	response := &ExplainResult{}
+	err := db.Client.PostContext(ctx, u, args, response)
+	return response, err
+}
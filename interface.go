@@ -1,6 +1,12 @@
 package couchdb
 
+import (
+	"context"
+	"time"
+)
+
 type DatabaseService interface {
+	DatabaseServiceContext
 	AllDocs(params *QueryParameters) (*ViewResponse, error)
 	AllDesignDocs() ([]DesignDocument, error)
 	Find(*FindArgs, interface{}) error
@@ -21,10 +27,68 @@ type DatabaseService interface {
 	IndependAttachmentMeta(docid, name, rev string) (*IndependAttachment, error)
 	PutIndependAttachment(docid string, att *IndependAttachment, rev string) (*DocumentResponse, error)
 	DeleteIndependAttachment(docid, name, rev string) (*DocumentResponse, error)
+	GetInto(ctx context.Context, id string, out interface{}) error
+	AllDocsInto(ctx context.Context, params *QueryParameters, out interface{}) error
+	AllDocsRows(ctx context.Context, params *QueryParameters) (*Rows, error)
+	FindRows(ctx context.Context, args *FindArgs) (*Rows, error)
+	AllDocsQueries(ctx context.Context, queries []QueryParameters) ([]ViewResponse, error)
+	DesignDocsQueries(ctx context.Context, queries []QueryParameters) ([]ViewResponse, error)
+	Info(ctx context.Context) (*DatabaseInfo, error)
+	Compact(ctx context.Context) error
+	CompactDesign(ctx context.Context, ddoc string) error
+	CompactAndWait(ctx context.Context, interval time.Duration) error
+	ViewCleanup(ctx context.Context) error
+	EnsureFullCommit(ctx context.Context) error
+	RevsLimit(ctx context.Context) (int, error)
+	SetRevsLimit(ctx context.Context, limit int) error
+	Changes(ctx context.Context, opts *ChangesOptions) (*Changes, error)
+	ListIndexes(ctx context.Context) (*IndexList, error)
+	DeleteIndex(ctx context.Context, ddoc, name string) error
+	Explain(ctx context.Context, args *FindArgs) (*ExplainResult, error)
+	Search(ctx context.Context, ddoc, index string, params *SearchParams) (*SearchResult, error)
+	NouveauSearch(ctx context.Context, ddoc, index string, params *NouveauParams) (*NouveauResult, error)
+	BulkGet(ctx context.Context, refs []BulkGetRef, opts *BulkGetOptions) ([]BulkGetResult, error)
+	GetWithOptions(ctx context.Context, id string, opts *GetOptions, out interface{}) error
+	GetMeta(ctx context.Context, id string, opts *GetOptions) (*DocumentMeta, error)
+	OpenRevs(ctx context.Context, id string, opts *GetOptions) ([]OpenRev, error)
+	Conflicts(ctx context.Context, design string, params QueryParameters) ([]ConflictedDoc, error)
+	Resolve(ctx context.Context, id string, resolve Resolver) ([]DocumentResponse, error)
+	Update(ctx context.Context, id string, doc CouchDoc, update func(doc CouchDoc) error, opts *UpdateOptions) (*DocumentResponse, error)
+}
+
+// DatabaseServiceContext holds the context-aware variants of the DatabaseService methods.
+// Cancelling ctx or letting its deadline pass aborts the underlying HTTP request.
+type DatabaseServiceContext interface {
+	AllDocsContext(ctx context.Context, params *QueryParameters) (*ViewResponse, error)
+	AllDesignDocsContext(ctx context.Context) ([]DesignDocument, error)
+	FindContext(ctx context.Context, args *FindArgs, out interface{}) error
+	CreateIndexContext(ctx context.Context, args *Index) (*CouchIndexBody, error)
+	RevContext(ctx context.Context, id string) (string, error)
+	GetContext(ctx context.Context, doc CouchDoc, id string) error
+	PutContext(ctx context.Context, doc CouchDoc) (*DocumentResponse, error)
+	PostContext(ctx context.Context, doc CouchDoc) (*DocumentResponse, error)
+	DeleteContext(ctx context.Context, doc CouchDoc) (*DocumentResponse, error)
+	StoreContext(ctx context.Context, doc CouchDoc) (*DocumentResponse, error)
+	MultiStoreContext(ctx context.Context, docs []CouchDoc) (error, []string, []string)
+	PutAttachmentToDocContext(ctx context.Context, doc CouchDoc, path string) (*DocumentResponse, error)
+	BulkContext(ctx context.Context, docs []CouchDoc) ([]DocumentResponse, error)
+	PurgeContext(ctx context.Context, req map[string][]string) (*PurgeResponse, error)
+	SeedContext(ctx context.Context, cache []DesignDocument) error
+	IndependAttachmentContext(ctx context.Context, docid, name, rev string) (*IndependAttachment, error)
+	IndependAttachmentMetaContext(ctx context.Context, docid, name, rev string) (*IndependAttachment, error)
+	PutIndependAttachmentContext(ctx context.Context, docid string, att *IndependAttachment, rev string) (*DocumentResponse, error)
+	DeleteIndependAttachmentContext(ctx context.Context, docid, name, rev string) (*DocumentResponse, error)
 }
 
 // ViewService is an interface for dealing with a view inside a CouchDB database.
 type ViewService interface {
 	Get(name string, params QueryParameters) (*ViewResponse, error)
 	Post(name string, keys []string, params QueryParameters) (*ViewResponse, error)
+	GetContext(ctx context.Context, name string, params QueryParameters) (*ViewResponse, error)
+	PostContext(ctx context.Context, name string, keys []string, params QueryParameters) (*ViewResponse, error)
+	GetInto(ctx context.Context, name string, params QueryParameters, out interface{}) error
+	PostInto(ctx context.Context, name string, keys []string, params QueryParameters, out interface{}) error
+	GetRows(ctx context.Context, name string, params QueryParameters) (*Rows, error)
+	PostRows(ctx context.Context, name string, keys []string, params QueryParameters) (*Rows, error)
+	Queries(ctx context.Context, name string, queries []QueryParameters) ([]ViewResponse, error)
 }
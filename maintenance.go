@@ -0,0 +1,92 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Info returns information about the database.
+// http://docs.couchdb.org/en/stable/api/database/common.html#get--db
+func (db *Database) Info(ctx context.Context) (*DatabaseInfo, error) {
+	u := fmt.Sprintf("%s/%s", db.Host, url.PathEscape(db.Name))
+	response := &DatabaseInfo{}
+	err := db.Client.GetContext(ctx, u, response)
+	return response, err
+}
+
+// Compact starts compaction of the database. It returns as soon as
+// compaction is running, see CompactAndWait to wait for its end.
+// http://docs.couchdb.org/en/stable/api/database/compact.html#db-compact
+func (db *Database) Compact(ctx context.Context) error {
+	u := fmt.Sprintf("%s/%s/_compact", db.Host, url.PathEscape(db.Name))
+	return db.Client.PostContext(ctx, u, struct{}{}, nil)
+}
+
+// CompactDesign starts compaction of the view indexes of the design document ddoc,
+// given without the "_design/" prefix.
+func (db *Database) CompactDesign(ctx context.Context, ddoc string) error {
+	u := fmt.Sprintf("%s/%s/_compact/%s", db.Host, url.PathEscape(db.Name), url.PathEscape(ddoc))
+	return db.Client.PostContext(ctx, u, struct{}{}, nil)
+}
+
+// defaultCompactInterval is the polling interval of CompactAndWait if none is given.
+const defaultCompactInterval = time.Second
+
+// CompactAndWait starts compaction and polls Info every interval, or every
+// second if interval is not positive, until compaction has finished or ctx is done.
+// Since compaction starts asynchronously, the first poll happens after one interval.
+func (db *Database) CompactAndWait(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultCompactInterval
+	}
+	if err := db.Compact(ctx); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		info, err := db.Info(ctx)
+		if err != nil {
+			return err
+		}
+		if !info.CompactRunning {
+			return nil
+		}
+	}
+}
+
+// ViewCleanup removes view index files no longer required by any design document.
+// http://docs.couchdb.org/en/stable/api/database/compact.html#db-view-cleanup
+func (db *Database) ViewCleanup(ctx context.Context) error {
+	u := fmt.Sprintf("%s/%s/_view_cleanup", db.Host, url.PathEscape(db.Name))
+	return db.Client.PostContext(ctx, u, struct{}{}, nil)
+}
+
+// EnsureFullCommit commits recent changes to disk. CouchDB 3.x treats it as a no-op.
+// http://docs.couchdb.org/en/stable/api/database/compact.html#db-ensure-full-commit
+func (db *Database) EnsureFullCommit(ctx context.Context) error {
+	u := fmt.Sprintf("%s/%s/_ensure_full_commit", db.Host, url.PathEscape(db.Name))
+	return db.Client.PostContext(ctx, u, struct{}{}, nil)
+}
+
+// RevsLimit returns the maximum number of revisions tracked per document.
+// http://docs.couchdb.org/en/stable/api/database/misc.html#db-revs-limit
+func (db *Database) RevsLimit(ctx context.Context) (int, error) {
+	u := fmt.Sprintf("%s/%s/_revs_limit", db.Host, url.PathEscape(db.Name))
+	var limit int
+	err := db.Client.GetContext(ctx, u, &limit)
+	return limit, err
+}
+
+// SetRevsLimit sets the maximum number of revisions tracked per document.
+func (db *Database) SetRevsLimit(ctx context.Context, limit int) error {
+	u := fmt.Sprintf("%s/%s/_revs_limit", db.Host, url.PathEscape(db.Name))
+	return db.Client.PutContext(ctx, u, limit, nil)
+}
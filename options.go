@@ -0,0 +1,112 @@
+package couchdb
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Option configures a CouchDBClient created by NewClient.
+// Options may be given in any order: WithTransport, WithTLSConfig and
+// WithTimeout apply to a copy of the client passed to WithHTTPClient.
+type Option func(*CouchDBClient)
+
+// httpOptions collects the options concerning the http.Client until NewClient builds it.
+type httpOptions struct {
+	client    *http.Client
+	transport http.RoundTripper
+	tlsConfig *tls.Config
+	timeout   *time.Duration
+}
+
+// WithHTTPClient makes the client send all requests through hc.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *CouchDBClient) {
+		c.httpOptions.client = hc
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for requests.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *CouchDBClient) {
+		c.httpOptions.transport = rt
+	}
+}
+
+// WithTLSConfig sets the TLS configuration, e.g. a custom CA bundle or client certificates.
+// It clones the *http.Transport in use (or http.DefaultTransport) before changing it.
+// Requests fail if the transport is another http.RoundTripper, which cannot be configured.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *CouchDBClient) {
+		c.httpOptions.tlsConfig = cfg
+	}
+}
+
+// WithTimeout limits the time taken by a single request, including reading the response body.
+func WithTimeout(d time.Duration) Option {
+	return func(c *CouchDBClient) {
+		c.httpOptions.timeout = &d
+	}
+}
+
+// WithAuth sets the authentication scheme, replacing the basic auth credentials passed to NewClient.
+func WithAuth(a Authenticator) Option {
+	return func(c *CouchDBClient) {
+		c.auth = a
+	}
+}
+
+// WithRetry enables retries of transient failures following p, see DefaultRetryPolicy.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *CouchDBClient) {
+		c.retry = &p
+	}
+}
+
+// WithHeader adds a header that is sent with every request.
+func WithHeader(key, value string) Option {
+	return func(c *CouchDBClient) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *CouchDBClient) {
+		c.userAgent = ua
+	}
+}
+
+// build returns the http.Client configured by o. The client passed to
+// WithHTTPClient, or http.DefaultClient, is copied rather than changed.
+func (o *httpOptions) build() (*http.Client, error) {
+	hc := http.DefaultClient
+	if o.client != nil {
+		hc = o.client
+	}
+	if o.transport == nil && o.tlsConfig == nil && o.timeout == nil {
+		return hc, nil
+	}
+	copied := *hc
+	if o.transport != nil {
+		copied.Transport = o.transport
+	}
+	if o.tlsConfig != nil {
+		rt := copied.Transport
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		t, ok := rt.(*http.Transport)
+		if !ok {
+			return &copied, fmt.Errorf("couchdb: WithTLSConfig requires an *http.Transport, not %T", rt)
+		}
+		t = t.Clone()
+		t.TLSClientConfig = o.tlsConfig
+		copied.Transport = t
+	}
+	if o.timeout != nil {
+		copied.Timeout = *o.timeout
+	}
+	return &copied, nil
+}
@@ -0,0 +1,87 @@
+package couchdb
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	cfg := &tls.Config{ServerName: "couchdb.example.com"}
+	tests := map[string]func(rt *http.Transport) []Option{
+		"transport then tls": func(rt *http.Transport) []Option {
+			return []Option{WithTransport(rt), WithTLSConfig(cfg)}
+		},
+		"tls then transport": func(rt *http.Transport) []Option {
+			return []Option{WithTLSConfig(cfg), WithTransport(rt)}
+		},
+		"client then tls": func(rt *http.Transport) []Option {
+			return []Option{WithHTTPClient(&http.Client{Transport: rt}), WithTLSConfig(cfg)}
+		},
+		"tls then client": func(rt *http.Transport) []Option {
+			return []Option{WithTLSConfig(cfg), WithHTTPClient(&http.Client{Transport: rt})}
+		},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			rt := &http.Transport{MaxIdleConns: 7}
+			c := NewClient("http://localhost:5984", "", "", opts(rt)...)
+			if c.err != nil {
+				t.Fatal(c.err)
+			}
+			got, ok := c.client.Transport.(*http.Transport)
+			if !ok || got.TLSClientConfig != cfg || got.MaxIdleConns != 7 {
+				t.Errorf("transport = %#v, want a clone of the given one with the TLS config", c.client.Transport)
+			}
+			// Clone may set up HTTP/2 on rt, but must not hand out rt itself
+			if got == rt || rt.TLSClientConfig == cfg {
+				t.Error("the caller's transport was modified")
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	for _, timeoutFirst := range []bool{false, true} {
+		hc := &http.Client{}
+		opts := []Option{WithHTTPClient(hc), WithTimeout(5 * time.Second)}
+		if timeoutFirst {
+			opts[0], opts[1] = opts[1], opts[0]
+		}
+		c := NewClient("http://localhost:5984", "", "", opts...)
+		if c.client.Timeout != 5*time.Second {
+			t.Errorf("timeout = %v, want 5s", c.client.Timeout)
+		}
+		if hc.Timeout != 0 || http.DefaultClient.Timeout != 0 {
+			t.Error("WithTimeout modified the caller's client")
+		}
+	}
+}
+
+func TestWithTLSConfigCustomRoundTripper(t *testing.T) {
+	called := false
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return nil, http.ErrNotSupported
+	})
+	c := NewClient("http://localhost:5984", "", "", WithTransport(rt), WithTLSConfig(&tls.Config{}))
+	err := c.GetContext(context.Background(), "http://localhost:5984/db", nil)
+	if err == nil || !strings.Contains(err.Error(), "WithTLSConfig") {
+		t.Errorf("err = %v, want the WithTLSConfig error", err)
+	}
+	if called {
+		t.Error("the request was sent")
+	}
+	if c.client.Transport == nil {
+		t.Error("the caller's RoundTripper was discarded")
+	}
+}
@@ -0,0 +1,189 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Paginator pages through a view or _all_docs with the startkey and
+// startkey_docid technique instead of the slow skip parameter.
+// Every request asks for one row more than the page size; that row starts
+// the next page and is handed out as an opaque page token, so a REST API can
+// return the token to its clients and resume from it with Page.
+// http://docs.couchdb.org/en/stable/ddocs/views/pagination.html
+type Paginator[K, V, D any] struct {
+	fetch    func(ctx context.Context, params QueryParameters, out interface{}) error
+	params   QueryParameters
+	pageSize int
+	invalid  error // reported by every Page if the parameters cannot be paged
+
+	token string
+	page  *Page[K, V, D]
+	err   error
+	done  bool
+}
+
+// Page is one page of rows. NextToken is empty for the last page.
+type Page[K, V, D any] struct {
+	Rows      []RowOf[K, V, D]
+	TotalRows int
+	NextToken string
+}
+
+// pageResponse also keeps the raw JSON of the row keys, since a K may not
+// encode back to the key it was decoded from.
+type pageResponse[K, V, D any] struct {
+	ViewResponseOf[K, V, D]
+	keys []json.RawMessage
+}
+
+func (r *pageResponse[K, V, D]) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &r.ViewResponseOf); err != nil {
+		return err
+	}
+	raw := struct {
+		Rows []struct {
+			Key json.RawMessage `json:"key"`
+		} `json:"rows"`
+	}{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	r.keys = make([]json.RawMessage, len(raw.Rows))
+	for i, row := range raw.Rows {
+		r.keys[i] = row.Key
+	}
+	return nil
+}
+
+type pageToken struct {
+	Key   json.RawMessage `json:"k"`
+	DocID string          `json:"id,omitempty"`
+}
+
+// NewViewPaginator returns a Paginator over the view name with pageSize rows per page.
+// params may set Descending, EndKey and IncludeDocs; StartKey only applies to the first page.
+// Keys cannot be paged this way and make every Page fail.
+// Reduce defaults to false, so views with a reduce function are paged over their map rows.
+func NewViewPaginator[K, V, D any](v ViewService, name string, params QueryParameters, pageSize int) *Paginator[K, V, D] {
+	if params.Reduce == nil {
+		reduce := false
+		params.Reduce = &reduce
+	}
+	return &Paginator[K, V, D]{
+		fetch: func(ctx context.Context, params QueryParameters, out interface{}) error {
+			return v.GetInto(ctx, name, params, out)
+		},
+		params:   params,
+		pageSize: pageSize,
+		invalid:  checkPageParams(params, pageSize),
+	}
+}
+
+// NewAllDocsPaginator returns a Paginator over _all_docs with pageSize rows per page.
+func NewAllDocsPaginator[D any](db DatabaseService, params QueryParameters, pageSize int) *Paginator[string, AllDocsValue, D] {
+	return &Paginator[string, AllDocsValue, D]{
+		fetch: func(ctx context.Context, params QueryParameters, out interface{}) error {
+			return db.AllDocsInto(ctx, &params, out)
+		},
+		params:   params,
+		pageSize: pageSize,
+		invalid:  checkPageParams(params, pageSize),
+	}
+}
+
+func checkPageParams(params QueryParameters, pageSize int) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("couchdb: invalid page size %d", pageSize)
+	}
+	if params.Keys != nil {
+		return errors.New("couchdb: keys cannot be paged with startkey")
+	}
+	return nil
+}
+
+// Page returns the page starting at token, or the first page if token is empty.
+func (p *Paginator[K, V, D]) Page(ctx context.Context, token string) (*Page[K, V, D], error) {
+	if p.invalid != nil {
+		return nil, p.invalid
+	}
+	params := p.params
+	params.Skip = nil
+	limit := p.pageSize + 1
+	params.Limit = &limit
+	if token != "" {
+		t, err := decodePageToken(token)
+		if err != nil {
+			return nil, err
+		}
+		params.StartKey = t.Key
+		params.StartKeyDocID = nil
+		if t.DocID != "" {
+			params.StartKeyDocID = &t.DocID
+		}
+	}
+	response := &pageResponse[K, V, D]{}
+	if err := p.fetch(ctx, params, response); err != nil {
+		return nil, err
+	}
+	page := &Page[K, V, D]{Rows: response.Rows, TotalRows: response.TotalRows}
+	if len(response.Rows) > p.pageSize {
+		next := response.Rows[p.pageSize]
+		page.Rows = response.Rows[:p.pageSize]
+		var err error
+		if page.NextToken, err = encodePageToken(pageToken{Key: response.keys[p.pageSize], DocID: next.ID}); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// Next fetches the following page. It returns false after the last page or on error.
+func (p *Paginator[K, V, D]) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	page, err := p.Page(ctx, p.token)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+	p.page = page
+	p.token = page.NextToken
+	p.done = page.NextToken == ""
+	return true
+}
+
+// Current returns the page fetched by the last call to Next.
+func (p *Paginator[K, V, D]) Current() *Page[K, V, D] {
+	return p.page
+}
+
+// Err returns the error that ended the iteration, if any.
+func (p *Paginator[K, V, D]) Err() error {
+	return p.err
+}
+
+func encodePageToken(t pageToken) (string, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodePageToken(token string) (pageToken, error) {
+	var t pageToken
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return t, fmt.Errorf("couchdb: invalid page token: %w", err)
+	}
+	if err := json.Unmarshal(b, &t); err != nil || len(t.Key) == 0 {
+		return t, fmt.Errorf("couchdb: invalid page token")
+	}
+	return t, nil
+}
@@ -0,0 +1,147 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type viewRow struct {
+	id  string
+	key string // raw JSON
+}
+
+// viewServer serves rows, given in collation order, from a view honouring
+// limit, descending, startkey and startkey_docid. The start key must be one of
+// the row keys byte for byte.
+func viewServer(t *testing.T, rows []viewRow) DatabaseService {
+	return testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		ordered := rows
+		if q.Get("descending") == "true" {
+			ordered = make([]viewRow, len(rows))
+			for i, row := range rows {
+				ordered[len(rows)-1-i] = row
+			}
+		}
+		start := 0
+		if key := q.Get("startkey"); key != "" {
+			start = -1
+			for i, row := range ordered {
+				if row.key == key && (q.Get("startkey_docid") == "" || row.id == q.Get("startkey_docid")) {
+					start = i
+					break
+				}
+			}
+			if start < 0 {
+				t.Errorf("startkey %s, startkey_docid %q match no row", key, q.Get("startkey_docid"))
+				start = len(ordered)
+			}
+		}
+		end := len(ordered)
+		if limit, err := strconv.Atoi(q.Get("limit")); err == nil && start+limit < end {
+			end = start + limit
+		}
+		var out []string
+		for _, row := range ordered[start:end] {
+			out = append(out, fmt.Sprintf(`{"id":%q,"key":%s,"value":null}`, row.id, row.key))
+		}
+		fmt.Fprintf(w, `{"total_rows":%d,"offset":%d,"rows":[%s]}`, len(rows), start, strings.Join(out, ","))
+	})
+}
+
+func pageIDs(t *testing.T, p *Paginator[interface{}, interface{}, interface{}]) []string {
+	t.Helper()
+	var pages []string
+	for p.Next(context.Background()) {
+		var ids []string
+		for _, row := range p.Current().Rows {
+			ids = append(ids, row.ID)
+		}
+		pages = append(pages, strings.Join(ids, ","))
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return pages
+}
+
+var paginateRows = []viewRow{
+	{"a", "1"}, {"b", "1"}, {"c", "1"},
+	{"d", `["x",{"deep":true}]`},
+	{"e", "12345678901234567890"},
+	{"f", "12345678901234567891"},
+}
+
+func TestPaginator(t *testing.T) {
+	tests := []struct {
+		name   string
+		params QueryParameters
+		want   string
+	}{
+		// duplicate keys continue with startkey_docid; large numbers do not survive a float64
+		{"ascending", QueryParameters{}, "a,b|c,d|e,f"},
+		{"descending", QueryParameters{Descending: boolPtr(true)}, "f,e|d,c|b,a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := viewServer(t, paginateRows)
+			p := NewViewPaginator[interface{}, interface{}, interface{}](db.View("d"), "v", tt.params, 2)
+			if got := strings.Join(pageIDs(t, p), "|"); got != tt.want {
+				t.Errorf("pages = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginatorToken(t *testing.T) {
+	db := viewServer(t, paginateRows)
+	p := NewViewPaginator[interface{}, interface{}, interface{}](db.View("d"), "v", QueryParameters{}, 4)
+	page, err := p.Page(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := base64.RawURLEncoding.DecodeString(page.NextToken)
+	if string(b) != `{"k":12345678901234567890,"id":"e"}` {
+		t.Errorf("token = %s", b)
+	}
+	page, err = p.Page(context.Background(), page.NextToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Rows) != 2 || page.Rows[0].ID != "e" || page.NextToken != "" {
+		t.Errorf("second page = %+v", page)
+	}
+}
+
+func TestPaginatorInvalid(t *testing.T) {
+	db := viewServer(t, paginateRows)
+	p := NewViewPaginator[interface{}, interface{}, interface{}](db.View("d"), "v", QueryParameters{}, 2)
+	noKey, _ := json.Marshal(map[string]string{"id": "a"})
+	for _, token := range []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("not json")),
+		base64.RawURLEncoding.EncodeToString(noKey),
+	} {
+		if _, err := p.Page(context.Background(), token); err == nil {
+			t.Errorf("Page(%q) did not fail", token)
+		}
+	}
+
+	keys := NewViewPaginator[interface{}, interface{}, interface{}](db.View("d"), "v", QueryParameters{Keys: []interface{}{1}}, 2)
+	if _, err := keys.Page(context.Background(), ""); err == nil {
+		t.Error("Page() with Keys did not fail")
+	}
+	allDocs := NewAllDocsPaginator[interface{}](db, QueryParameters{Keys: []interface{}{"a"}}, 2)
+	if allDocs.Next(context.Background()) || allDocs.Err() == nil {
+		t.Error("Next() with Keys did not fail")
+	}
+	if _, err := NewViewPaginator[interface{}, interface{}, interface{}](db.View("d"), "v", QueryParameters{}, 0).Page(context.Background(), ""); err == nil {
+		t.Error("Page() with page size 0 did not fail")
+	}
+}
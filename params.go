@@ -0,0 +1,162 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+)
+
+// Collation bounds for view keys. MinKey (null) sorts before and MaxKey ({})
+// after all other values, e.g. EndKey: []interface{}{"user", MaxKey}.
+// http://docs.couchdb.org/en/stable/ddocs/views/collation.html
+var (
+	MinKey = collationBound("null")
+	MaxKey = collationBound("{}")
+)
+
+type collationBound string
+
+func (b collationBound) MarshalJSON() ([]byte, error) {
+	return []byte(b), nil
+}
+
+// maxGetKeysLength is the size up to which Keys are sent in the query string instead of a POST body.
+const maxGetKeysLength = 1024
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// Validate reports combinations of parameters that CouchDB rejects or that contradict each other.
+func (p *QueryParameters) Validate() error {
+	if p == nil {
+		return nil
+	}
+	reduceOff := p.Reduce != nil && !*p.Reduce
+	switch {
+	case reduceOff && isTrue(p.Group):
+		return errors.New("couchdb: group requires reduce")
+	case reduceOff && p.GroupLevel != nil:
+		return errors.New("couchdb: group_level requires reduce")
+	case isTrue(p.IncludeDocs) && (isTrue(p.Reduce) || isTrue(p.Group) || p.GroupLevel != nil):
+		return errors.New("couchdb: include_docs is invalid for reduce queries")
+	case p.GroupLevel != nil && *p.GroupLevel < 0:
+		return errors.New("couchdb: group_level must not be negative")
+	case p.Limit != nil && *p.Limit < 0:
+		return errors.New("couchdb: limit must not be negative")
+	case p.Skip != nil && *p.Skip < 0:
+		return errors.New("couchdb: skip must not be negative")
+	case p.Key != nil && p.Keys != nil:
+		return errors.New("couchdb: key and keys are mutually exclusive")
+	case p.Keys != nil && (p.StartKey != nil || p.EndKey != nil):
+		return errors.New("couchdb: keys cannot be combined with startkey or endkey")
+	}
+	// Key, StartKey and EndKey used to be pre-encoded *string values,
+	// which would now be encoded a second time.
+	for _, k := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"key", p.Key},
+		{"startkey", p.StartKey},
+		{"endkey", p.EndKey},
+	} {
+		if _, ok := k.value.(*string); ok {
+			return fmt.Errorf("couchdb: %s must be the key value, not a *string", k.name)
+		}
+	}
+	if p.Update != nil {
+		switch *p.Update {
+		case UpdateTrue, UpdateFalse, UpdateLazy:
+		default:
+			return fmt.Errorf("couchdb: invalid update value %q", *p.Update)
+		}
+	}
+	return nil
+}
+
+// values validates p and returns its query string with Key, StartKey and EndKey JSON encoded.
+func (p *QueryParameters) values() (url.Values, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	q, err := query.Values(p)
+	if err != nil || p == nil {
+		return q, err
+	}
+	for _, k := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"key", p.Key},
+		{"startkey", p.StartKey},
+		{"endkey", p.EndKey},
+	} {
+		if k.value == nil {
+			continue
+		}
+		b, err := json.Marshal(k.value)
+		if err != nil {
+			return nil, fmt.Errorf("couchdb: encode %s: %w", k.name, err)
+		}
+		q.Set(k.name, string(b))
+	}
+	return q, nil
+}
+
+// url returns base with the query string of p, moved into p.Partition if set.
+func (p *QueryParameters) url(base string) (string, error) {
+	q, err := p.values()
+	if err != nil {
+		return "", err
+	}
+	if p != nil && p.Partition != "" {
+		base = partitionURL(base, p.Partition)
+	}
+	return fmt.Sprintf("%s?%s", base, q.Encode()), nil
+}
+
+// request returns method, URL and body for querying the view or _all_docs at base.
+// Keys are sent as keys parameter of a GET request if they are short
+// and in the body of a POST request otherwise.
+func (p *QueryParameters) request(base string) (string, string, interface{}, error) {
+	q, err := p.values()
+	if err != nil {
+		return "", "", nil, err
+	}
+	if p != nil && p.Partition != "" {
+		base = partitionURL(base, p.Partition)
+	}
+	if p == nil || p.Keys == nil {
+		return "GET", fmt.Sprintf("%s?%s", base, q.Encode()), nil, nil
+	}
+	b, err := json.Marshal(p.Keys)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("couchdb: encode keys: %w", err)
+	}
+	if len(b) <= maxGetKeysLength {
+		q.Set("keys", string(b))
+		return "GET", fmt.Sprintf("%s?%s", base, q.Encode()), nil, nil
+	}
+	content := struct {
+		Keys []interface{} `json:"keys"`
+	}{
+		Keys: p.Keys,
+	}
+	return "POST", fmt.Sprintf("%s?%s", base, q.Encode()), content, nil
+}
+
+// partitionURL moves a view or _all_docs URL into the partition, e.g.
+// {db}/_all_docs becomes {db}/_partition/{partition}/_all_docs.
+func partitionURL(base, partition string) string {
+	for _, sep := range []string{"/_design/", "/_all_docs"} {
+		if i := strings.LastIndex(base, sep); i >= 0 {
+			return fmt.Sprintf("%s/_partition/%s%s", base[:i], url.PathEscape(partition), base[i:])
+		}
+	}
+	return base
+}
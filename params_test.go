@@ -0,0 +1,117 @@
+package couchdb
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func boolPtr(b bool) *bool    { return &b }
+func intPtr(i int) *int       { return &i }
+func strPtr(s string) *string { return &s }
+
+func TestQueryParametersValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params QueryParameters
+		valid  bool
+	}{
+		{"empty", QueryParameters{}, true},
+		{"group", QueryParameters{Group: boolPtr(true)}, true},
+		{"group without reduce", QueryParameters{Reduce: boolPtr(false), Group: boolPtr(true)}, false},
+		{"group_level without reduce", QueryParameters{Reduce: boolPtr(false), GroupLevel: intPtr(1)}, false},
+		{"include_docs with reduce", QueryParameters{Reduce: boolPtr(true), IncludeDocs: boolPtr(true)}, false},
+		{"include_docs without reduce", QueryParameters{Reduce: boolPtr(false), IncludeDocs: boolPtr(true)}, true},
+		{"negative group_level", QueryParameters{GroupLevel: intPtr(-1)}, false},
+		{"negative limit", QueryParameters{Limit: intPtr(-1)}, false},
+		{"negative skip", QueryParameters{Skip: intPtr(-1)}, false},
+		{"key and keys", QueryParameters{Key: "a", Keys: []interface{}{"b"}}, false},
+		{"keys and startkey", QueryParameters{Keys: []interface{}{"b"}, StartKey: "a"}, false},
+		{"update lazy", QueryParameters{Update: strPtr(UpdateLazy)}, true},
+		{"invalid update", QueryParameters{Update: strPtr("sometimes")}, false},
+		{"*string key", QueryParameters{Key: strPtr(`"a"`)}, false},
+		{"*string endkey", QueryParameters{EndKey: strPtr(`"a"`)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestQueryParametersValues(t *testing.T) {
+	p := &QueryParameters{
+		Key:           []interface{}{"user", 1},
+		StartKey:      "a",
+		EndKey:        []interface{}{"user", MaxKey},
+		EndKeyDocID:   strPtr("z"),
+		StartKeyDocID: strPtr("a"),
+		Limit:         intPtr(10),
+	}
+	q, err := p.values()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"key":            {`["user",1]`},
+		"startkey":       {`"a"`},
+		"endkey":         {`["user",{}]`},
+		"endkey_docid":   {"z"},
+		"startkey_docid": {"a"},
+		"limit":          {"10"},
+	}
+	if q.Encode() != want.Encode() {
+		t.Errorf("values() = %s, want %s", q.Encode(), want.Encode())
+	}
+}
+
+func TestQueryParametersRequestKeys(t *testing.T) {
+	const base = "http://localhost:5984/db/_design/d/_view/v"
+	short := &QueryParameters{Keys: []interface{}{"a", "b"}}
+	method, u, body, err := short.request(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "GET" || body != nil || !strings.Contains(u, "keys="+url.QueryEscape(`["a","b"]`)) {
+		t.Errorf("request() = %s %s %v", method, u, body)
+	}
+
+	var keys []interface{}
+	for len(keys)*5 <= maxGetKeysLength {
+		keys = append(keys, "key")
+	}
+	long := &QueryParameters{Keys: keys}
+	method, u, body, err = long.request(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "POST" || body == nil || strings.Contains(u, "keys=") {
+		t.Errorf("request() = %s %s, body %v", method, u, body != nil)
+	}
+}
+
+func TestPartitionURL(t *testing.T) {
+	tests := []struct {
+		base, want string
+	}{
+		{"http://h/db/_all_docs", "http://h/db/_partition/p%2F1/_all_docs"},
+		{"http://h/db/_design/d/_view/v", "http://h/db/_partition/p%2F1/_design/d/_view/v"},
+		{"http://h/db/_find", "http://h/db/_find"},
+	}
+	for _, tt := range tests {
+		if got := partitionURL(tt.base, "p/1"); got != tt.want {
+			t.Errorf("partitionURL(%q) = %q, want %q", tt.base, got, tt.want)
+		}
+	}
+	p := &QueryParameters{Partition: "sensor"}
+	u, err := p.url("http://h/db/_all_docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(u, "http://h/db/_partition/sensor/_all_docs?") {
+		t.Errorf("url() = %q", u)
+	}
+}
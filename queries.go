@@ -0,0 +1,88 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// multiQuery is the request body of the /queries endpoints.
+type multiQuery struct {
+	Queries []map[string]interface{} `json:"queries"`
+}
+
+type multiQueryResponse struct {
+	Results []ViewResponse `json:"results"`
+}
+
+// stringParameters are the query parameters whose values are plain strings rather than JSON.
+var stringParameters = map[string]bool{
+	"stale":          true,
+	"startkey_docid": true,
+	"endkey_docid":   true,
+}
+
+// object returns p as JSON object for the /queries endpoints,
+// which do not support Partition.
+func (p *QueryParameters) object() (map[string]interface{}, error) {
+	if p.Partition != "" {
+		return nil, errors.New("couchdb: partition is not supported by multi-query requests")
+	}
+	q, err := p.values()
+	if err != nil {
+		return nil, err
+	}
+	obj := make(map[string]interface{}, len(q))
+	for k := range q {
+		v := q.Get(k)
+		if stringParameters[k] || (k == "update" && v == UpdateLazy) {
+			obj[k] = v
+		} else {
+			obj[k] = json.RawMessage(v)
+		}
+	}
+	if p.Keys != nil {
+		obj["keys"] = p.Keys
+	}
+	return obj, nil
+}
+
+func runQueries(ctx context.Context, c *CouchDBClient, u string, queries []QueryParameters) ([]ViewResponse, error) {
+	content := multiQuery{Queries: make([]map[string]interface{}, len(queries))}
+	for i := range queries {
+		obj, err := queries[i].object()
+		if err != nil {
+			return nil, fmt.Errorf("couchdb: query %d: %w", i, err)
+		}
+		content.Queries[i] = obj
+	}
+	response := &multiQueryResponse{}
+	if err := c.PostContext(ctx, u, content, response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+// Queries runs several queries against the view name in a single request
+// and returns one response per query, in the same order.
+// Partition is not supported by this endpoint and rejected.
+// http://docs.couchdb.org/en/stable/api/ddoc/views.html#sending-multiple-queries-to-a-view
+func (v *View) Queries(ctx context.Context, name string, queries []QueryParameters) ([]ViewResponse, error) {
+	u := fmt.Sprintf("%s_view/%s/queries", v.URL, name)
+	return runQueries(ctx, v.Client, u, queries)
+}
+
+// AllDocsQueries runs several queries against _all_docs in a single request.
+// http://docs.couchdb.org/en/stable/api/database/bulk-api.html#sending-multiple-queries-to-a-database
+func (db *Database) AllDocsQueries(ctx context.Context, queries []QueryParameters) ([]ViewResponse, error) {
+	u := fmt.Sprintf("%s/%s/_all_docs/queries", db.Host, url.PathEscape(db.Name))
+	return runQueries(ctx, db.Client, u, queries)
+}
+
+// DesignDocsQueries runs several queries against _design_docs in a single request.
+func (db *Database) DesignDocsQueries(ctx context.Context, queries []QueryParameters) ([]ViewResponse, error) {
+	u := fmt.Sprintf("%s/%s/_design_docs/queries", db.Host, url.PathEscape(db.Name))
+	return runQueries(ctx, db.Client, u, queries)
+}
@@ -0,0 +1,98 @@
+package couchdb
+
+import "context"
+
+// Stats is the value of the built-in _stats reduce function.
+type Stats struct {
+	Sum    float64 `json:"sum"`
+	Count  int64   `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Sumsqr float64 `json:"sumsqr"`
+}
+
+// Mean returns the average of the reduced values.
+func (s Stats) Mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// GroupRow is a row of a grouped reduce query.
+type GroupRow[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// Reduce runs the reduce function of the view name over all rows selected
+// by params and decodes the single result into V. It returns the zero V if
+// no rows match.
+func Reduce[V any](ctx context.Context, v ViewService, name string, params QueryParameters) (V, error) {
+	reduce := true
+	params.Reduce = &reduce
+	params.Group = nil
+	params.GroupLevel = nil
+	var value V
+	res, err := QueryView[interface{}, V, struct{}](ctx, v, name, params)
+	if err != nil {
+		return value, err
+	}
+	if len(res.Rows) > 0 {
+		value = res.Rows[0].Value
+	}
+	return value, nil
+}
+
+// ReduceCount returns the result of a view with the _count reduce function.
+func ReduceCount(ctx context.Context, v ViewService, name string, params QueryParameters) (int64, error) {
+	return Reduce[int64](ctx, v, name, params)
+}
+
+// ReduceSum returns the result of a view with the _sum reduce function over numbers.
+// Use Reduce with a slice type for views summing arrays.
+func ReduceSum(ctx context.Context, v ViewService, name string, params QueryParameters) (float64, error) {
+	return Reduce[float64](ctx, v, name, params)
+}
+
+// ReduceStats returns the result of a view with the _stats reduce function.
+func ReduceStats(ctx context.Context, v ViewService, name string, params QueryParameters) (Stats, error) {
+	return Reduce[Stats](ctx, v, name, params)
+}
+
+// ReduceApproxCountDistinct returns the result of a view with the _approx_count_distinct reduce function.
+func ReduceApproxCountDistinct(ctx context.Context, v ViewService, name string, params QueryParameters) (int64, error) {
+	return Reduce[int64](ctx, v, name, params)
+}
+
+// Group runs the reduce function of the view name grouped by the full key.
+func Group[K, V any](ctx context.Context, v ViewService, name string, params QueryParameters) ([]GroupRow[K, V], error) {
+	reduce, group := true, true
+	params.Reduce = &reduce
+	params.Group = &group
+	params.GroupLevel = nil
+	return groupRows[K, V](ctx, v, name, params)
+}
+
+// GroupLevel runs the reduce function of the view name grouped by the first
+// level elements of array keys. K is typically a slice or a struct decoding
+// the truncated key array, e.g. [year, month] at level 2.
+func GroupLevel[K, V any](ctx context.Context, v ViewService, name string, level int, params QueryParameters) ([]GroupRow[K, V], error) {
+	reduce := true
+	params.Reduce = &reduce
+	params.Group = nil
+	params.GroupLevel = &level
+	return groupRows[K, V](ctx, v, name, params)
+}
+
+func groupRows[K, V any](ctx context.Context, v ViewService, name string, params QueryParameters) ([]GroupRow[K, V], error) {
+	res, err := QueryView[K, V, struct{}](ctx, v, name, params)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]GroupRow[K, V], len(res.Rows))
+	for i, row := range res.Rows {
+		rows[i] = GroupRow[K, V]{Key: row.Key, Value: row.Value}
+	}
+	return rows, nil
+}
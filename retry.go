@@ -0,0 +1,145 @@
+package couchdb
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the client retries requests that failed with a
+// transient error, i.e. a transport error or one of the Statuses.
+type RetryPolicy struct {
+	MaxAttempts int           // Total number of attempts including the first one. Values below 2 disable retries.
+	MinBackoff  time.Duration // Wait before the first retry, doubled for every further retry.
+	MaxBackoff  time.Duration // Upper bound of the wait, defaults to that of DefaultRetryPolicy. A longer Retry-After ends the retries.
+	Statuses    []int         // Response status codes that are retried.
+	Methods     []string      // HTTP methods that are retried. POST is only retried if listed here.
+}
+
+// DefaultRetryPolicy returns a policy retrying idempotent requests up to three times
+// on 429, 500, 502, 503 and 504 responses and on transport errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  100 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+		Statuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		Methods: []string{"GET", "HEAD", "PUT", "DELETE", "OPTIONS"},
+	}
+}
+
+func (p *RetryPolicy) retryable(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if p == nil || attempt >= p.MaxAttempts || req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if !containsString(p.Methods, req.Method) {
+		return false
+	}
+	if err != nil {
+		var uerr *url.Error
+		return errors.As(err, &uerr)
+	}
+	return containsInt(p.Statuses, resp.StatusCode)
+}
+
+// backoff returns the wait before the given retry, honouring a Retry-After header of resp.
+// It returns false if Retry-After asks to wait longer than MaxBackoff.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) (time.Duration, bool) {
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = DefaultRetryPolicy().MaxBackoff
+	}
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return d, d <= max
+		}
+	}
+	d := p.MinBackoff << uint(attempt-1)
+	if d < p.MinBackoff || d > max {
+		// capped, or the shift overflowed
+		d = max
+	}
+	if d <= 0 {
+		return 0, true
+	}
+	// jitter within [d/2, d]
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)), true
+}
+
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// send executes req, retrying it according to the client's retry policy.
+func (c *CouchDBClient) send(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendAuthenticated(req)
+		if !c.retry.retryable(req, resp, err, attempt) {
+			return resp, err
+		}
+		wait, ok := c.retry.backoff(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, i int) bool {
+	for _, v := range list {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingServer answers every request with status and counts the attempts.
+func failingServer(t *testing.T, status int, retryAfter string, attempts *int32, opts ...Option) *CouchDBClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(attempts, 1)
+		io.Copy(ioutil.Discard, r.Body)
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":"unavailable","reason":"try again"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, "", "", opts...)
+}
+
+func fastRetry() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.MinBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+func TestRetryStatuses(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		var attempts int32
+		c := failingServer(t, status, "", &attempts, WithRetry(fastRetry()))
+		err := c.GetContext(context.Background(), c.host+"/db", nil)
+		if !ErrorStatus(err, status) {
+			t.Errorf("status %d: err = %v", status, err)
+		}
+		if attempts != 3 {
+			t.Errorf("status %d: %d attempts, want 3", status, attempts)
+		}
+	}
+}
+
+func TestRetryNotRetried(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		do     func(c *CouchDBClient) error
+	}{
+		{"POST", http.StatusServiceUnavailable, func(c *CouchDBClient) error {
+			return c.PostContext(context.Background(), c.host+"/db", map[string]string{}, nil)
+		}},
+		{"404", http.StatusNotFound, func(c *CouchDBClient) error {
+			return c.GetContext(context.Background(), c.host+"/db", nil)
+		}},
+		{"body without GetBody", http.StatusServiceUnavailable, func(c *CouchDBClient) error {
+			body := io.MultiReader(bytes.NewReader([]byte("data")))
+			return c.PutWithDataContext(context.Background(), c.host+"/db/a/att", body, nil, "text/plain")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			c := failingServer(t, tt.status, "", &attempts, WithRetry(fastRetry()))
+			if err := tt.do(c); !ErrorStatus(err, tt.status) {
+				t.Errorf("err = %v", err)
+			}
+			if attempts != 1 {
+				t.Errorf("%d attempts, want 1", attempts)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var attempts int32
+	p := fastRetry()
+	p.MaxAttempts = 2
+	p.MaxBackoff = 2 * time.Second
+	c := failingServer(t, http.StatusServiceUnavailable, "1", &attempts, WithRetry(p))
+	start := time.Now()
+	c.GetContext(context.Background(), c.host+"/db", nil)
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want Retry-After of 1s", elapsed)
+	}
+	if attempts != 2 {
+		t.Errorf("%d attempts, want 2", attempts)
+	}
+}
+
+func TestRetryAfterAboveMaxBackoff(t *testing.T) {
+	var attempts int32
+	c := failingServer(t, http.StatusServiceUnavailable, "86400", &attempts, WithRetry(fastRetry()))
+	err := c.GetContext(context.Background(), c.host+"/db", nil)
+	if !ErrorStatus(err, http.StatusServiceUnavailable) || attempts != 1 {
+		t.Errorf("err = %v after %d attempts, want 503 after 1", err, attempts)
+	}
+}
+
+func TestRetryCancelDuringBackoff(t *testing.T) {
+	var attempts int32
+	p := DefaultRetryPolicy()
+	p.MinBackoff = time.Minute
+	p.MaxBackoff = time.Minute
+	c := failingServer(t, http.StatusServiceUnavailable, "", &attempts, WithRetry(p))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := c.GetContext(ctx, c.host+"/db", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second || attempts != 1 {
+		t.Errorf("returned after %v and %d attempts", elapsed, attempts)
+	}
+}
+
+func TestRetryZeroMaxBackoff(t *testing.T) {
+	var attempts int32
+	c := failingServer(t, http.StatusServiceUnavailable, "", &attempts, WithRetry(RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  20 * time.Millisecond,
+		Statuses:    []int{http.StatusServiceUnavailable},
+		Methods:     []string{"GET"},
+	}))
+	start := time.Now()
+	c.GetContext(context.Background(), c.host+"/db", nil)
+	// waits of 20, 40 and 80ms with jitter down to half of them
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("4 attempts took %v, MinBackoff was ignored", elapsed)
+	}
+	if attempts != 4 {
+		t.Errorf("%d attempts, want 4", attempts)
+	}
+
+	// Retry-After is still honoured up to the default MaxBackoff
+	attempts = 0
+	c = failingServer(t, http.StatusServiceUnavailable, "0", &attempts, WithRetry(RetryPolicy{MaxAttempts: 2, Statuses: []int{http.StatusServiceUnavailable}, Methods: []string{"GET"}}))
+	c.GetContext(context.Background(), c.host+"/db", nil)
+	if attempts != 2 {
+		t.Errorf("%d attempts with Retry-After and zero MaxBackoff, want 2", attempts)
+	}
+}
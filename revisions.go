@@ -0,0 +1,189 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// AllRevs requests every leaf revision as GetOptions.OpenRevs.
+const AllRevs = "all"
+
+// GetOptions are the query parameters of GET /{db}/{docid}.
+// http://docs.couchdb.org/en/stable/api/document/common.html#get--db-docid
+type GetOptions struct {
+	Rev              string   // Fetch this revision instead of the winning one
+	Revs             bool     // Include the _revisions history
+	RevsInfo         bool     // Include _revs_info with the availability of every known revision
+	OpenRevs         []string // Fetch these leaf revisions, or all of them if AllRevs is the only entry
+	Conflicts        bool     // Include _conflicts
+	DeletedConflicts bool     // Include _deleted_conflicts
+	Latest           bool     // Return the latest leaf revisions instead of the requested ones
+	LocalSeq         bool     // Include _local_seq
+	Meta             bool     // Same as Conflicts, DeletedConflicts and RevsInfo together
+	Attachments      bool     // Include attachment content
+	AttsSince        []string // Only include attachments changed since these revisions
+}
+
+func (o *GetOptions) values() (url.Values, error) {
+	q := url.Values{}
+	if o == nil {
+		return q, nil
+	}
+	if o.Rev != "" {
+		q.Set("rev", o.Rev)
+	}
+	for _, b := range []struct {
+		name  string
+		value bool
+	}{
+		{"revs", o.Revs},
+		{"revs_info", o.RevsInfo},
+		{"conflicts", o.Conflicts},
+		{"deleted_conflicts", o.DeletedConflicts},
+		{"latest", o.Latest},
+		{"local_seq", o.LocalSeq},
+		{"meta", o.Meta},
+		{"attachments", o.Attachments},
+	} {
+		if b.value {
+			q.Set(b.name, "true")
+		}
+	}
+	if len(o.OpenRevs) == 1 && o.OpenRevs[0] == AllRevs {
+		q.Set("open_revs", AllRevs)
+	} else if len(o.OpenRevs) > 0 {
+		b, err := json.Marshal(o.OpenRevs)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("open_revs", string(b))
+	}
+	if len(o.AttsSince) > 0 {
+		b, err := json.Marshal(o.AttsSince)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("atts_since", string(b))
+	}
+	return q, nil
+}
+
+// Revisions is the _revisions history of a document, newest first.
+type Revisions struct {
+	Start int      `json:"start"`
+	IDs   []string `json:"ids"`
+}
+
+// Revs returns the full revision strings, e.g. "3-abc", newest first.
+func (r *Revisions) Revs() []string {
+	revs := make([]string, len(r.IDs))
+	for i, id := range r.IDs {
+		revs[i] = strconv.Itoa(r.Start-i) + "-" + id
+	}
+	return revs
+}
+
+// Revision statuses reported in _revs_info.
+const (
+	RevAvailable = "available"
+	RevMissing   = "missing"
+	RevDeleted   = "deleted"
+)
+
+// RevInfo is an entry of _revs_info.
+type RevInfo struct {
+	Rev    string `json:"rev"`
+	Status string `json:"status"`
+}
+
+// DocumentMeta holds the revision metadata of a document requested with GetOptions.
+type DocumentMeta struct {
+	ID               string     `json:"_id"`
+	Rev              string     `json:"_rev"`
+	Deleted          bool       `json:"_deleted,omitempty"`
+	Revisions        *Revisions `json:"_revisions,omitempty"`
+	RevsInfo         []RevInfo  `json:"_revs_info,omitempty"`
+	Conflicts        []string   `json:"_conflicts,omitempty"`
+	DeletedConflicts []string   `json:"_deleted_conflicts,omitempty"`
+	LocalSeq         Seq        `json:"_local_seq,omitempty"`
+}
+
+// OpenRev is the result for one revision requested with GetOptions.OpenRevs:
+// either the document or the revision that is missing.
+type OpenRev struct {
+	OK      json.RawMessage `json:"ok,omitempty"`
+	Missing string          `json:"missing,omitempty"`
+}
+
+// Meta decodes the revision metadata of the document.
+func (r *OpenRev) Meta() (*DocumentMeta, error) {
+	meta := &DocumentMeta{}
+	if err := r.ScanDoc(meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// ScanDoc decodes the document into v.
+// It returns an error matching ErrNotFound if the revision is missing.
+func (r *OpenRev) ScanDoc(v interface{}) error {
+	if r.OK == nil {
+		return fmt.Errorf("couchdb: revision %s: %w", r.Missing, ErrNotFound)
+	}
+	return json.Unmarshal(r.OK, v)
+}
+
+// GetWithOptions is like GetInto but sends the query parameters in opts.
+// Use OpenRevs instead if opts.OpenRevs is set.
+func (db *Database) GetWithOptions(ctx context.Context, id string, opts *GetOptions, out interface{}) error {
+	if opts != nil && len(opts.OpenRevs) > 0 {
+		return errors.New("couchdb: use OpenRevs to get open_revs")
+	}
+	q, err := opts.values()
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/%s/%s?%s", db.Host, url.PathEscape(db.Name), docPath(id), q.Encode())
+	return db.Client.GetContext(ctx, u, out)
+}
+
+// GetMeta returns the revision metadata of document id, e.g. its _conflicts or _revisions.
+func (db *Database) GetMeta(ctx context.Context, id string, opts *GetOptions) (*DocumentMeta, error) {
+	meta := &DocumentMeta{}
+	if err := db.GetWithOptions(ctx, id, opts, meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// OpenRevs fetches the revisions in opts.OpenRevs of document id, or all leaf revisions
+// if opts.OpenRevs is empty. The results are in the order CouchDB returns them.
+func (db *Database) OpenRevs(ctx context.Context, id string, opts *GetOptions) ([]OpenRev, error) {
+	o := GetOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if len(o.OpenRevs) == 0 {
+		o.OpenRevs = []string{AllRevs}
+	}
+	q, err := o.values()
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/%s/%s?%s", db.Host, url.PathEscape(db.Name), docPath(id), q.Encode())
+	body, err := db.Client.openWithHeader(ctx, u, "GET", nil, http.Header{"Accept": {"application/json"}})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var revs []OpenRev
+	if err := json.NewDecoder(body).Decode(&revs); err != nil {
+		return nil, err
+	}
+	return revs, nil
+}
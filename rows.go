@@ -0,0 +1,239 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Rows streams the rows of a view, _all_docs or _find response.
+// Rows are decoded one at a time as Next is called, so the response is never
+// held in memory as a whole. Metadata placed after the rows by CouchDB, like
+// the bookmark of _find, is available once Next has returned false.
+//
+//	rows, err := db.AllDocsRows(ctx, &params)
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//		var row couchdb.Row
+//		if err := rows.Scan(&row); err != nil {
+//			return err
+//		}
+//	}
+//	return rows.Err()
+type Rows struct {
+	body      io.ReadCloser
+	method    string
+	url       string
+	dec       *json.Decoder
+	field     string // name of the array holding the rows
+	started   bool
+	inArray   bool
+	row       json.RawMessage
+	err       error
+	closed    bool
+	errorCode string
+	reason    string
+
+	totalRows      int
+	offset         int
+	updateSeq      Seq
+	bookmark       string
+	warning        string
+	executionStats *ExecutionStats
+}
+
+func newRows(body io.ReadCloser, method, url, field string) *Rows {
+	return &Rows{body: body, method: method, url: url, dec: json.NewDecoder(body), field: field}
+}
+
+// AllDocsRows is like AllDocsContext but streams the rows.
+func (db *Database) AllDocsRows(ctx context.Context, params *QueryParameters) (*Rows, error) {
+	base := fmt.Sprintf("%s/%s/_all_docs", db.Host, url.PathEscape(db.Name))
+	method, u, in, err := params.request(base)
+	if err != nil {
+		return nil, err
+	}
+	body, err := db.Client.open(ctx, u, method, in)
+	if err != nil {
+		return nil, err
+	}
+	return newRows(body, method, u, "rows"), nil
+}
+
+// FindRows is like FindContext but streams the matching documents.
+func (db *Database) FindRows(ctx context.Context, args *FindArgs) (*Rows, error) {
+	u := fmt.Sprintf("%s/%s/_find", db.Host, url.PathEscape(db.Name))
+	body, err := db.Client.open(ctx, u, "POST", args)
+	if err != nil {
+		return nil, err
+	}
+	return newRows(body, "POST", u, "docs"), nil
+}
+
+// GetRows is like GetContext but streams the rows.
+func (v *View) GetRows(ctx context.Context, name string, params QueryParameters) (*Rows, error) {
+	method, uri, in, err := params.request(fmt.Sprintf("%s_view/%s", v.URL, name))
+	if err != nil {
+		return nil, err
+	}
+	body, err := v.Client.open(ctx, uri, method, in)
+	if err != nil {
+		return nil, err
+	}
+	return newRows(body, method, uri, "rows"), nil
+}
+
+// PostRows is like PostContext but streams the rows.
+func (v *View) PostRows(ctx context.Context, name string, keys []string, params QueryParameters) (*Rows, error) {
+	content, uri, err := v.postRequest(name, keys, params)
+	if err != nil {
+		return nil, err
+	}
+	body, err := v.Client.open(ctx, uri, "POST", content)
+	if err != nil {
+		return nil, err
+	}
+	return newRows(body, "POST", uri, "rows"), nil
+}
+
+// Next advances to the next row. It returns false at the end of the rows or on error.
+func (r *Rows) Next() bool {
+	if r.closed {
+		r.row = nil
+		return false
+	}
+	row, err := r.next()
+	if err != nil {
+		if err != io.EOF {
+			r.err = err
+		} else if r.errorCode != "" {
+			// CouchDB reports errors hit while streaming after the rows
+			r.err = &Error{Method: r.method, URL: r.url, StatusCode: http.StatusOK, ErrorCode: r.errorCode, Reason: r.reason}
+		}
+		r.row = nil
+		r.Close()
+		return false
+	}
+	r.row = row
+	return true
+}
+
+func (r *Rows) next() (json.RawMessage, error) {
+	if !r.started {
+		if err := expectDelim(r.dec, '{'); err != nil {
+			return nil, err
+		}
+		r.started = true
+	}
+	for {
+		if r.inArray {
+			if r.dec.More() {
+				var row json.RawMessage
+				if err := r.dec.Decode(&row); err != nil {
+					return nil, err
+				}
+				return row, nil
+			}
+			if err := expectDelim(r.dec, ']'); err != nil {
+				return nil, err
+			}
+			r.inArray = false
+			continue
+		}
+		if !r.dec.More() {
+			return nil, io.EOF
+		}
+		key, err := r.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case r.field:
+			if err := expectDelim(r.dec, '['); err != nil {
+				return nil, err
+			}
+			r.inArray = true
+		case "total_rows":
+			err = r.dec.Decode(&r.totalRows)
+		case "offset":
+			err = r.dec.Decode(&r.offset)
+		case "update_seq":
+			err = r.dec.Decode(&r.updateSeq)
+		case "bookmark":
+			err = r.dec.Decode(&r.bookmark)
+		case "warning":
+			err = r.dec.Decode(&r.warning)
+		case "execution_stats":
+			r.executionStats = &ExecutionStats{}
+			err = r.dec.Decode(r.executionStats)
+		case "error":
+			err = r.dec.Decode(&r.errorCode)
+		case "reason":
+			err = r.dec.Decode(&r.reason)
+		default:
+			err = r.dec.Decode(&json.RawMessage{})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Scan decodes the current row into dest, e.g. a *Row for views or a document for _find.
+func (r *Rows) Scan(dest interface{}) error {
+	if r.row == nil {
+		return fmt.Errorf("couchdb: Scan called without a current row")
+	}
+	return json.Unmarshal(r.row, dest)
+}
+
+// Err returns the error that ended the iteration, if any.
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close releases the response. It is safe to call Close more than once.
+func (r *Rows) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.body.Close()
+}
+
+// TotalRows returns total_rows of a view or _all_docs response.
+func (r *Rows) TotalRows() int {
+	return r.totalRows
+}
+
+// Offset returns the offset of a view or _all_docs response.
+func (r *Rows) Offset() int {
+	return r.offset
+}
+
+// UpdateSeq returns update_seq if it was requested with UpdateSeq.
+func (r *Rows) UpdateSeq() Seq {
+	return r.updateSeq
+}
+
+// Bookmark returns the bookmark of a _find response.
+func (r *Rows) Bookmark() string {
+	return r.bookmark
+}
+
+// Warning returns the warning of a _find response.
+func (r *Rows) Warning() string {
+	return r.warning
+}
+
+// ExecutionStats returns the execution statistics of a _find response
+// if they were requested with FindArgs.ExecutionStats.
+func (r *Rows) ExecutionStats() *ExecutionStats {
+	return r.executionStats
+}
@@ -0,0 +1,155 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRowsMetadataBeforeRows(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_rows":10,"offset":2,"update_seq":"5-g","rows":[
+			{"id":"a","key":"a","value":{"rev":"1-x"}},
+			{"id":"b","key":"b","value":{"rev":"1-y"}}
+		]}`))
+	})
+	rows, err := db.AllDocsRows(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		if rows.TotalRows() != 10 || rows.Offset() != 2 {
+			t.Errorf("TotalRows() = %d, Offset() = %d while iterating", rows.TotalRows(), rows.Offset())
+		}
+		var row Row
+		if err := rows.Scan(&row); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, row.ID)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Errorf("ids = %v", ids)
+	}
+	if rows.UpdateSeq() != "5-g" {
+		t.Errorf("UpdateSeq() = %q", rows.UpdateSeq())
+	}
+}
+
+func TestRowsMetadataAfterRows(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"docs":[{"_id":"a"},{"_id":"b"},{"_id":"c"}],
+			"bookmark":"g1AAAA","warning":"No matching index found",
+			"execution_stats":{"total_docs_examined":3,"results_returned":3}}`))
+	})
+	rows, err := db.FindRows(context.Background(), &FindArgs{Selector: map[string]interface{}{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("%d rows, want 3", n)
+	}
+	if rows.Bookmark() != "g1AAAA" {
+		t.Errorf("Bookmark() = %q", rows.Bookmark())
+	}
+	if rows.Warning() != "No matching index found" {
+		t.Errorf("Warning() = %q", rows.Warning())
+	}
+	if s := rows.ExecutionStats(); s == nil || s.TotalDocsExamined != 3 || s.ResultsReturned != 3 {
+		t.Errorf("ExecutionStats() = %+v", s)
+	}
+}
+
+func TestRowsErrorAfterRows(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_rows":2,"offset":0,"rows":[{"id":"a","key":"a","value":1}],
+			"error":"timeout","reason":"The request could not be processed in a reasonable amount of time."}`))
+	})
+	rows, err := db.View("d").GetRows(context.Background(), "v", QueryParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	if n != 1 {
+		t.Errorf("%d rows, want 1", n)
+	}
+	var dberr *Error
+	if !errors.As(rows.Err(), &dberr) || dberr.ErrorCode != "timeout" || dberr.StatusCode != http.StatusOK {
+		t.Errorf("Err() = %v, want timeout error", rows.Err())
+	}
+}
+
+func TestRowsClose(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_rows":1000,"offset":0,"rows":[{"id":"a","key":"a","value":1},`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	rows, err := db.AllDocsRows(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rows.Next() {
+		t.Fatalf("Next() = false, Err() = %v", rows.Err())
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if rows.Next() {
+		t.Error("Next() = true after Close")
+	}
+	if err := rows.Scan(&Row{}); err == nil {
+		t.Error("Scan succeeded after Close")
+	}
+	if err := rows.Err(); err != nil {
+		t.Errorf("Err() = %v after Close", err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Errorf("second Close() = %v", err)
+	}
+}
+
+func TestViewPostKeys(t *testing.T) {
+	db := testDatabase(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s", r.Method)
+		}
+		w.Write([]byte(`{"total_rows":2,"offset":0,"rows":[{"id":"a","key":"a","value":1}]}`))
+	})
+	v := db.View("d")
+	rows, err := v.PostRows(context.Background(), "v", []string{"a"}, QueryParameters{})
+	if err != nil {
+		t.Fatalf("PostRows() = %v", err)
+	}
+	rows.Close()
+	params := QueryParameters{Keys: []interface{}{"b"}}
+	if _, err := v.PostRows(context.Background(), "v", []string{"a"}, params); err == nil {
+		t.Error("PostRows() with QueryParameters.Keys did not fail")
+	}
+	if _, err := v.PostContext(context.Background(), "v", nil, params); err == nil {
+		t.Error("PostContext() with QueryParameters.Keys did not fail")
+	}
+}
@@ -0,0 +1,165 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SearchParams are the parameters of a Clouseau search query.
+// http://docs.couchdb.org/en/stable/ddocs/search.html#queries
+type SearchParams struct {
+	Query         string                       `json:"q"`                        // Lucene query syntax
+	Sort          []string                     `json:"sort,omitempty"`           // e.g. "-price<number>"
+	Limit         int                          `json:"limit,omitempty"`          // Maximum number of hits, at most 200
+	Bookmark      string                       `json:"bookmark,omitempty"`       // Continue after a previous result
+	IncludeDocs   bool                         `json:"include_docs,omitempty"`   // Include the documents in the hits
+	IncludeFields []string                     `json:"include_fields,omitempty"` // Stored fields returned in SearchHit.Fields
+	Counts        []string                     `json:"counts,omitempty"`         // Facet fields to count values of
+	Ranges        map[string]map[string]string `json:"ranges,omitempty"`         // Facet field to label to range query, e.g. "[0 TO 100]"
+	Drilldown     [][]string                   `json:"drilldown,omitempty"`      // [field, value, ...] pairs restricting the hits
+	GroupField    string                       `json:"group_field,omitempty"`    // Group hits by this field
+	GroupLimit    int                          `json:"group_limit,omitempty"`
+	GroupSort     []string                     `json:"group_sort,omitempty"`
+	Highlights    []string                     `json:"highlight_fields,omitempty"`
+	Stale         string                       `json:"stale,omitempty"`
+}
+
+// SearchResult is the response of a Clouseau search query.
+// Hits is empty if GroupField was set, see Groups instead.
+type SearchResult struct {
+	TotalRows int                           `json:"total_rows"`
+	Bookmark  string                        `json:"bookmark"`
+	Hits      []SearchHit                   `json:"rows"`
+	Counts    map[string]map[string]float64 `json:"counts,omitempty"`
+	Ranges    map[string]map[string]float64 `json:"ranges,omitempty"`
+	Groups    []SearchGroup                 `json:"groups,omitempty"`
+}
+
+// SearchGroup is a group of hits when searching with GroupField.
+type SearchGroup struct {
+	By        interface{} `json:"by"`
+	TotalRows int         `json:"total_rows"`
+	Hits      []SearchHit `json:"rows"`
+}
+
+// SearchHit is a single hit of a Clouseau search.
+type SearchHit struct {
+	ID         string                 `json:"id"`
+	Order      []json.RawMessage      `json:"order"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Highlights map[string][]string    `json:"highlights,omitempty"`
+	Doc        json.RawMessage        `json:"doc,omitempty"`
+}
+
+// Score returns the relevance score of the hit. It is only
+// available when the results are sorted by relevance, the default.
+func (h *SearchHit) Score() (float64, bool) {
+	if len(h.Order) == 0 {
+		return 0, false
+	}
+	var score float64
+	if err := json.Unmarshal(h.Order[0], &score); err != nil {
+		return 0, false
+	}
+	return score, true
+}
+
+// ScanDoc decodes the document included with IncludeDocs into v.
+func (h *SearchHit) ScanDoc(v interface{}) error {
+	if len(h.Doc) == 0 {
+		return fmt.Errorf("couchdb: search hit %s has no document", h.ID)
+	}
+	return json.Unmarshal(h.Doc, v)
+}
+
+// NouveauParams are the parameters of a Nouveau search query.
+// http://docs.couchdb.org/en/stable/ddocs/nouveau.html#queries
+type NouveauParams struct {
+	Query       string                    `json:"q"`
+	Sort        []string                  `json:"sort,omitempty"`
+	Limit       int                       `json:"limit,omitempty"`
+	Bookmark    string                    `json:"bookmark,omitempty"`
+	IncludeDocs bool                      `json:"include_docs,omitempty"`
+	Counts      []string                  `json:"counts,omitempty"`
+	Ranges      map[string][]NouveauRange `json:"ranges,omitempty"`
+	Update      *bool                     `json:"update,omitempty"`
+	Locale      string                    `json:"locale,omitempty"`
+	TopN        int                       `json:"top_n,omitempty"`
+}
+
+// NouveauRange is a labelled range of a numeric facet.
+type NouveauRange struct {
+	Label        string  `json:"label"`
+	Min          float64 `json:"min"`
+	Max          float64 `json:"max"`
+	MinInclusive *bool   `json:"min_inclusive,omitempty"`
+	MaxInclusive *bool   `json:"max_inclusive,omitempty"`
+}
+
+// NouveauResult is the response of a Nouveau search query.
+type NouveauResult struct {
+	TotalHits         int                         `json:"total_hits"`
+	TotalHitsRelation string                      `json:"total_hits_relation"` // "EQUAL_TO" or "GREATER_THAN_OR_EQUAL_TO"
+	Bookmark          string                      `json:"bookmark"`
+	Hits              []NouveauHit                `json:"hits"`
+	Counts            map[string]map[string]int64 `json:"counts,omitempty"`
+	Ranges            map[string]map[string]int64 `json:"ranges,omitempty"`
+}
+
+// NouveauHit is a single hit of a Nouveau search.
+type NouveauHit struct {
+	ID     string                 `json:"id"`
+	Order  []NouveauSortValue     `json:"order"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Doc    json.RawMessage        `json:"doc,omitempty"`
+}
+
+// NouveauSortValue is a typed sort value of a NouveauHit.
+type NouveauSortValue struct {
+	Type  string      `json:"@type"`
+	Value interface{} `json:"value"`
+}
+
+// Score returns the relevance score of the hit. It is only
+// available when the results are sorted by relevance, the default.
+func (h *NouveauHit) Score() (float64, bool) {
+	if len(h.Order) == 0 || h.Order[0].Type != "float" {
+		return 0, false
+	}
+	score, ok := h.Order[0].Value.(float64)
+	return score, ok
+}
+
+// ScanDoc decodes the document included with IncludeDocs into v.
+func (h *NouveauHit) ScanDoc(v interface{}) error {
+	if len(h.Doc) == 0 {
+		return fmt.Errorf("couchdb: search hit %s has no document", h.ID)
+	}
+	return json.Unmarshal(h.Doc, v)
+}
+
+// Search queries the Clouseau search index of the design document ddoc,
+// which may be given with or without the "_design/" prefix.
+// The query is always sent as POST, which takes the same parameters as GET.
+// http://docs.couchdb.org/en/stable/api/ddoc/search.html
+func (db *Database) Search(ctx context.Context, ddoc, index string, params *SearchParams) (*SearchResult, error) {
+	u := fmt.Sprintf("%s/%s/_design/%s/_search/%s", db.Host, url.PathEscape(db.Name),
+		url.PathEscape(strings.TrimPrefix(ddoc, "_design/")), url.PathEscape(index))
+	response := &SearchResult{}
+	err := db.Client.PostContext(ctx, u, params, response)
+	return response, err
+}
+
+// NouveauSearch queries the Nouveau search index of the design document ddoc,
+// which may be given with or without the "_design/" prefix.
+// http://docs.couchdb.org/en/stable/api/ddoc/nouveau.html
+func (db *Database) NouveauSearch(ctx context.Context, ddoc, index string, params *NouveauParams) (*NouveauResult, error) {
+	u := fmt.Sprintf("%s/%s/_design/%s/_nouveau/%s", db.Host, url.PathEscape(db.Name),
+		url.PathEscape(strings.TrimPrefix(ddoc, "_design/")), url.PathEscape(index))
+	response := &NouveauResult{}
+	err := db.Client.PostContext(ctx, u, params, response)
+	return response, err
+}
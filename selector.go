@@ -0,0 +1,285 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Selector is a Mango selector built with Field, AllOf, AnyOf and NoneOf.
+// Invalid operator arguments are recorded and reported by Map, MarshalJSON and Query.Args.
+//
+//	sel := couchdb.Field("age").Gt(18).And(couchdb.Field("tags").ElemMatch(couchdb.Field("").Eq("admin")))
+//
+// http://docs.couchdb.org/en/stable/api/database/find.html#selector-syntax
+type Selector struct {
+	m   map[string]interface{}
+	err error
+}
+
+// FieldSelector builds conditions on a single document field.
+// Nested fields use dot notation, e.g. "address.city".
+type FieldSelector struct {
+	name string
+}
+
+// Field starts a condition on the field name. An empty name applies the
+// condition to the value itself, as needed for array elements in ElemMatch.
+func Field(name string) FieldSelector {
+	return FieldSelector{name: name}
+}
+
+func (f FieldSelector) op(op string, arg interface{}) Selector {
+	if err := validateOperator(op, arg); err != nil {
+		return Selector{err: fmt.Errorf("couchdb: field %q: %w", f.name, err)}
+	}
+	if s, ok := arg.(Selector); ok {
+		if s.err != nil {
+			return s
+		}
+		arg = s.m
+	}
+	if f.name == "" {
+		return Selector{m: map[string]interface{}{op: arg}}
+	}
+	return Selector{m: map[string]interface{}{f.name: map[string]interface{}{op: arg}}}
+}
+
+// Op adds a condition with any operator, validating the argument for the known ones.
+func (f FieldSelector) Op(op string, arg interface{}) Selector {
+	return f.op(op, arg)
+}
+
+func (f FieldSelector) Eq(v interface{}) Selector  { return f.op(Eq, v) }
+func (f FieldSelector) Ne(v interface{}) Selector  { return f.op(Ne, v) }
+func (f FieldSelector) Lt(v interface{}) Selector  { return f.op(Lt, v) }
+func (f FieldSelector) Lte(v interface{}) Selector { return f.op(Lte, v) }
+func (f FieldSelector) Gt(v interface{}) Selector  { return f.op(Gt, v) }
+func (f FieldSelector) Gte(v interface{}) Selector { return f.op(Gte, v) }
+
+// Exists matches documents that have (or, with false, lack) the field.
+func (f FieldSelector) Exists(exists bool) Selector { return f.op(Exists, exists) }
+
+// Type matches the JSON type of the field: "null", "boolean", "number", "string", "array" or "object".
+func (f FieldSelector) Type(t string) Selector { return f.op(Type, t) }
+
+// In matches if the field equals one of values.
+func (f FieldSelector) In(values ...interface{}) Selector { return f.op(In, values) }
+
+// Nin matches if the field equals none of values.
+func (f FieldSelector) Nin(values ...interface{}) Selector { return f.op(Nin, values) }
+
+// All matches an array field containing all of values.
+func (f FieldSelector) All(values ...interface{}) Selector { return f.op(All, values) }
+
+// Size matches an array field of length n.
+func (f FieldSelector) Size(n int) Selector { return f.op(Size, n) }
+
+// Mod matches an integer field for which field % divisor == remainder.
+func (f FieldSelector) Mod(divisor, remainder int) Selector {
+	return f.op(Mod, []int{divisor, remainder})
+}
+
+// Regex matches a string field against a PCRE pattern.
+func (f FieldSelector) Regex(pattern string) Selector { return f.op(Regex, pattern) }
+
+// ElemMatch matches an array field with at least one element matching s.
+func (f FieldSelector) ElemMatch(s Selector) Selector { return f.op(ElemMatch, s) }
+
+// AllMatch matches an array field whose elements all match s.
+func (f FieldSelector) AllMatch(s Selector) Selector { return f.op(AllMatch, s) }
+
+// AllOf combines selectors with $and.
+func AllOf(selectors ...Selector) Selector { return combine(And, selectors) }
+
+// AnyOf combines selectors with $or.
+func AnyOf(selectors ...Selector) Selector { return combine(Or, selectors) }
+
+// NoneOf combines selectors with $nor.
+func NoneOf(selectors ...Selector) Selector { return combine(Nor, selectors) }
+
+func combine(op string, selectors []Selector) Selector {
+	if len(selectors) == 0 {
+		return Selector{err: fmt.Errorf("couchdb: %s needs at least one selector", op)}
+	}
+	list := make([]interface{}, len(selectors))
+	for i, s := range selectors {
+		if s.err != nil {
+			return s
+		}
+		list[i] = s.m
+	}
+	return Selector{m: map[string]interface{}{op: list}}
+}
+
+// And returns a selector matching s and all others.
+func (s Selector) And(others ...Selector) Selector {
+	return AllOf(append([]Selector{s}, others...)...)
+}
+
+// Or returns a selector matching s or any of others.
+func (s Selector) Or(others ...Selector) Selector {
+	return AnyOf(append([]Selector{s}, others...)...)
+}
+
+// Not returns a selector matching documents that s does not match.
+func (s Selector) Not() Selector {
+	if s.err != nil {
+		return s
+	}
+	return Selector{m: map[string]interface{}{Not: s.m}}
+}
+
+// Err returns the first invalid operator argument found while building s.
+func (s Selector) Err() error {
+	return s.err
+}
+
+// Map returns the selector as used in FindArgs.Selector, ChangesOptions.Selector
+// or a partial_filter_selector.
+func (s Selector) Map() (map[string]interface{}, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.m == nil {
+		return map[string]interface{}{}, nil
+	}
+	return s.m, nil
+}
+
+func (s Selector) MarshalJSON() ([]byte, error) {
+	m, err := s.Map()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+var selectorTypes = map[string]bool{
+	"null": true, "boolean": true, "number": true, "string": true, "array": true, "object": true,
+}
+
+// validateOperator checks the shape of arg for the operators defined in consts.go.
+func validateOperator(op string, arg interface{}) error {
+	switch op {
+	case Exists:
+		if _, ok := arg.(bool); !ok {
+			return fmt.Errorf("%s needs a boolean, got %T", op, arg)
+		}
+	case Type:
+		t, ok := arg.(string)
+		if !ok || !selectorTypes[t] {
+			return fmt.Errorf("%s needs one of null, boolean, number, string, array or object, got %v", op, arg)
+		}
+	case Regex:
+		if _, ok := arg.(string); !ok {
+			return fmt.Errorf("%s needs a string, got %T", op, arg)
+		}
+	case Size:
+		if n, ok := arg.(int); !ok || n < 0 {
+			return fmt.Errorf("%s needs a non-negative integer, got %v", op, arg)
+		}
+	case Mod:
+		dr, ok := arg.([]int)
+		if !ok || len(dr) != 2 {
+			return fmt.Errorf("%s needs [divisor, remainder], got %v", op, arg)
+		}
+		if dr[0] == 0 {
+			return fmt.Errorf("%s divisor must not be 0", op)
+		}
+	case In, Nin, All:
+		if _, ok := arg.([]interface{}); !ok {
+			return fmt.Errorf("%s needs an array, got %T", op, arg)
+		}
+	case ElemMatch, AllMatch, Not:
+		if _, ok := arg.(Selector); !ok {
+			if _, ok := arg.(map[string]interface{}); !ok {
+				return fmt.Errorf("%s needs a selector, got %T", op, arg)
+			}
+		}
+	case And, Or, Nor:
+		if _, ok := arg.([]interface{}); !ok {
+			return fmt.Errorf("%s needs an array of selectors, got %T", op, arg)
+		}
+	}
+	return nil
+}
+
+// Query builds FindArgs around a Selector.
+type Query struct {
+	args FindArgs
+	err  error
+}
+
+// NewQuery starts a Mango query for documents matching s.
+func NewQuery(s Selector) *Query {
+	q := &Query{}
+	q.args.Selector, q.err = s.Map()
+	return q
+}
+
+// Sort adds a sort field with order Asc or Desc.
+func (q *Query) Sort(field, order string) *Query {
+	if order != Asc && order != Desc {
+		q.setErr(fmt.Errorf("couchdb: invalid sort order %q for field %q", order, field))
+		return q
+	}
+	q.args.Sort = append(q.args.Sort, map[string]string{field: order})
+	return q
+}
+
+// Fields restricts the returned documents to fields.
+func (q *Query) Fields(fields ...string) *Query {
+	for _, f := range fields {
+		q.args.Fields = append(q.args.Fields, f)
+	}
+	return q
+}
+
+// UseIndex instructs CouchDB to use the index in the design document ddoc,
+// optionally naming the index.
+func (q *Query) UseIndex(ddoc string, name ...string) *Query {
+	switch len(name) {
+	case 0:
+		q.args.UseIndex = ddoc
+	case 1:
+		q.args.UseIndex = []string{ddoc, name[0]}
+	default:
+		q.setErr(fmt.Errorf("couchdb: UseIndex takes at most one index name"))
+	}
+	return q
+}
+
+func (q *Query) Limit(n int64) *Query {
+	q.args.Limit = n
+	return q
+}
+
+func (q *Query) Skip(n int64) *Query {
+	q.args.Skip = n
+	return q
+}
+
+func (q *Query) Bookmark(bookmark string) *Query {
+	q.args.Bookmark = bookmark
+	return q
+}
+
+func (q *Query) ExecutionStats(enabled bool) *Query {
+	q.args.ExecutionStats = enabled
+	return q
+}
+
+func (q *Query) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// Args returns the FindArgs for Find, FindDocs or NewFindIterator.
+func (q *Query) Args() (*FindArgs, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	args := q.args
+	return &args, nil
+}
@@ -0,0 +1,90 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateOperator(t *testing.T) {
+	tests := []struct {
+		op    string
+		arg   interface{}
+		valid bool
+	}{
+		{Exists, true, true},
+		{Exists, "yes", false},
+		{Type, "string", true},
+		{Type, "date", false},
+		{Type, 1, false},
+		{Regex, "^a", true},
+		{Regex, 1, false},
+		{Size, 2, true},
+		{Size, -1, false},
+		{Size, "2", false},
+		{Mod, []int{4, 1}, true},
+		{Mod, []int{0, 1}, false},
+		{Mod, []int{4}, false},
+		{In, []interface{}{"a"}, true},
+		{Nin, "a", false},
+		{All, []interface{}{1, 2}, true},
+		{ElemMatch, Field("").Eq(1), true},
+		{ElemMatch, map[string]interface{}{"$eq": 1}, true},
+		{AllMatch, "a", false},
+		{Not, 1, false},
+		{Or, []interface{}{map[string]interface{}{}}, true},
+		{Nor, map[string]interface{}{}, false},
+		{Eq, nil, true},
+		{"$custom", 1, true},
+	}
+	for _, tt := range tests {
+		err := validateOperator(tt.op, tt.arg)
+		if (err == nil) != tt.valid {
+			t.Errorf("validateOperator(%s, %v) = %v, want valid %v", tt.op, tt.arg, err, tt.valid)
+		}
+	}
+}
+
+func TestSelectorJSON(t *testing.T) {
+	tests := []struct {
+		s    Selector
+		want string
+	}{
+		{Field("age").Gte(18), `{"age":{"$gte":18}}`},
+		{Field("tags").ElemMatch(Field("").Eq("go")), `{"tags":{"$elemMatch":{"$eq":"go"}}}`},
+		{Field("n").Mod(4, 1), `{"n":{"$mod":[4,1]}}`},
+		{Field("a").Eq(1).And(Field("b").In("x", "y")), `{"$and":[{"a":{"$eq":1}},{"b":{"$in":["x","y"]}}]}`},
+		{NoneOf(Field("a").Exists(true)), `{"$nor":[{"a":{"$exists":true}}]}`},
+		{Field("a").Eq(1).Not(), `{"$not":{"a":{"$eq":1}}}`},
+		{Selector{}, `{}`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.s)
+		if err != nil {
+			t.Errorf("Marshal(%s) = %v", tt.want, err)
+			continue
+		}
+		if string(b) != tt.want {
+			t.Errorf("Marshal() = %s, want %s", b, tt.want)
+		}
+	}
+}
+
+func TestSelectorErr(t *testing.T) {
+	tests := []Selector{
+		Field("a").Type("date"),
+		Field("a").Eq(1).And(Field("b").Size(-1)),
+		Field("a").Size(-1).Not(),
+		AnyOf(),
+	}
+	for i, s := range tests {
+		if s.Err() == nil {
+			t.Errorf("tests[%d].Err() = nil", i)
+		}
+		if _, err := s.Map(); err == nil {
+			t.Errorf("tests[%d].Map() did not fail", i)
+		}
+		if _, err := NewQuery(s).Args(); err == nil {
+			t.Errorf("tests[%d]: Query.Args() did not fail", i)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+)
+
+// CreateDBOptions are the optional query parameters of PUT /{db}.
+// http://docs.couchdb.org/en/stable/api/database/common.html#put--db
+type CreateDBOptions struct {
+	Q           int  `url:"q,omitempty"`           // Number of shards
+	N           int  `url:"n,omitempty"`           // Number of replicas
+	Partitioned bool `url:"partitioned,omitempty"` // Create a partitioned database
+}
+
+// AllDBsParameters are the query parameters of GET /_all_dbs.
+// StartKey and EndKey are plain database names.
+type AllDBsParameters struct {
+	Descending bool
+	StartKey   string
+	EndKey     string
+	Limit      int
+	Skip       int
+}
+
+// DBInfoResult is a single entry of the POST /_dbs_info response.
+// Info is nil and Error is set if the database does not exist.
+type DBInfoResult struct {
+	Key   string        `json:"key"`
+	Info  *DatabaseInfo `json:"info,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// CreateDB creates the database name.
+// It fails with ErrFileExists if the database already exists.
+func (c *CouchDBClient) CreateDB(ctx context.Context, name string, opts *CreateDBOptions) (DatabaseService, error) {
+	u := fmt.Sprintf("%s/%s", c.host, url.PathEscape(name))
+	if opts != nil {
+		q, err := query.Values(opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(q) > 0 {
+			u = fmt.Sprintf("%s?%s", u, q.Encode())
+		}
+	}
+	if err := c.PutContext(ctx, u, nil, nil); err != nil {
+		return nil, err
+	}
+	return c.Use(name), nil
+}
+
+// EnsureDB creates the database name unless it already exists.
+func (c *CouchDBClient) EnsureDB(ctx context.Context, name string, opts *CreateDBOptions) (DatabaseService, error) {
+	db, err := c.CreateDB(ctx, name, opts)
+	if errors.Is(err, ErrFileExists) {
+		return c.Use(name), nil
+	}
+	return db, err
+}
+
+// DeleteDB deletes the database name and all its documents.
+func (c *CouchDBClient) DeleteDB(ctx context.Context, name string) error {
+	u := fmt.Sprintf("%s/%s", c.host, url.PathEscape(name))
+	return c.DeleteContext(ctx, u, nil)
+}
+
+// DBExists checks with a HEAD request whether the database name exists.
+func (c *CouchDBClient) DBExists(ctx context.Context, name string) (bool, error) {
+	u := fmt.Sprintf("%s/%s", c.host, url.PathEscape(name))
+	resp, err := c.HeadContext(ctx, u)
+	if NotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// AllDBs returns the names of all databases on the server.
+// http://docs.couchdb.org/en/stable/api/server/common.html#all-dbs
+func (c *CouchDBClient) AllDBs(ctx context.Context, params *AllDBsParameters) ([]string, error) {
+	q := url.Values{}
+	if params != nil {
+		if params.Descending {
+			q.Set("descending", "true")
+		}
+		if params.StartKey != "" {
+			q.Set("start_key", strconv.Quote(params.StartKey))
+		}
+		if params.EndKey != "" {
+			q.Set("end_key", strconv.Quote(params.EndKey))
+		}
+		if params.Limit > 0 {
+			q.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Skip > 0 {
+			q.Set("skip", strconv.Itoa(params.Skip))
+		}
+	}
+	u := fmt.Sprintf("%s/_all_dbs?%s", c.host, q.Encode())
+	var names []string
+	err := c.GetContext(ctx, u, &names)
+	return names, err
+}
+
+// DBsInfo returns information about several databases in one request.
+// http://docs.couchdb.org/en/stable/api/server/common.html#dbs-info
+func (c *CouchDBClient) DBsInfo(ctx context.Context, names []string) ([]DBInfoResult, error) {
+	content := struct {
+		Keys []string `json:"keys"`
+	}{
+		Keys: names,
+	}
+	u := fmt.Sprintf("%s/_dbs_info", c.host)
+	response := []DBInfoResult{}
+	err := c.PostContext(ctx, u, content, &response)
+	return response, err
+}
@@ -1,9 +1,22 @@
 package couchdb
 
-import "strings"
+import (
+	"encoding/json"
+	"strings"
+)
 
 const langJavaScript = "javascript"
 
+// Values of QueryParameters.Update.
+const (
+	UpdateTrue  = "true"
+	UpdateFalse = "false"
+	UpdateLazy  = "lazy"
+)
+
+// QueryParameters are the query parameters of views and _all_docs.
+// They are checked with Validate before a request is sent.
+// http://docs.couchdb.org/en/stable/api/ddoc/views.html#db-design-design-doc-view-view-name
 type QueryParameters struct {
 	Conflicts       *bool   `url:"conflicts,omitempty"`
 	Descending      *bool   `url:"descending,omitempty"`
@@ -14,15 +27,79 @@ type QueryParameters struct {
 	InclusiveEnd    *bool   `url:"inclusive_end,omitempty"`
 	Reduce          *bool   `url:"reduce,omitempty"`
 	UpdateSeq       *bool   `url:"update_seq,omitempty"`
+	Stable          *bool   `url:"stable,omitempty"`
+	Sorted          *bool   `url:"sorted,omitempty"`
+	LocalSeq        *bool   `url:"local_seq,omitempty"`
 	GroupLevel      *int    `url:"group_level,omitempty"`
 	Limit           *int    `url:"limit,omitempty"`
 	Skip            *int    `url:"skip,omitempty"`
-	Key             *string `url:"key,omitempty"`
-	EndKey          *string `url:"endkey,comma,omitempty"`
-	EndKeyDocID     *string `url:"end_key_doc_id,omitempty"`
-	Stale           *string `url:"stale,omitempty"`
-	StartKey        *string `url:"startkey,comma,omitempty"`
+	EndKeyDocID     *string `url:"endkey_docid,omitempty"`
+	Stale           *string `url:"stale,omitempty"` // Deprecated: use Update and Stable
 	StartKeyDocID   *string `url:"startkey_docid,omitempty"`
+	Update          *string `url:"update,omitempty"` // UpdateTrue, UpdateFalse or UpdateLazy
+
+	// Key, StartKey, EndKey and Keys take any Go value, which is JSON encoded,
+	// including MinKey and MaxKey. They cover the start_key and end_key aliases
+	// of CouchDB, which are equivalent to startkey and endkey. A *string is
+	// rejected, since it used to hold the already JSON encoded key.
+	Key      interface{}   `url:"-"`
+	StartKey interface{}   `url:"-"`
+	EndKey   interface{}   `url:"-"`
+	Keys     []interface{} `url:"-"`
+
+	// Partition restricts the query to a partition of a partitioned database.
+	Partition string `url:"-"`
+}
+
+// Seq is an update sequence. CouchDB 2.x and later report opaque strings,
+// older versions plain numbers, which are kept in their decimal form.
+type Seq string
+
+func (s *Seq) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var v string
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*s = Seq(v)
+		return nil
+	}
+	if string(b) == "null" {
+		*s = ""
+		return nil
+	}
+	*s = Seq(b)
+	return nil
+}
+
+// DatabaseInfo describes GET /{db} response object.
+// http://docs.couchdb.org/en/stable/api/database/common.html#get--db
+type DatabaseInfo struct {
+	DBName            string        `json:"db_name"`
+	UpdateSeq         Seq           `json:"update_seq"`
+	PurgeSeq          Seq           `json:"purge_seq"`
+	DocCount          int64         `json:"doc_count"`
+	DocDelCount       int64         `json:"doc_del_count"`
+	CompactRunning    bool          `json:"compact_running"`
+	DiskFormatVersion int           `json:"disk_format_version"`
+	InstanceStartTime string        `json:"instance_start_time"`
+	Sizes             DatabaseSizes `json:"sizes"`
+	Cluster           struct {
+		Q int `json:"q"`
+		N int `json:"n"`
+		W int `json:"w"`
+		R int `json:"r"`
+	} `json:"cluster"`
+	Props struct {
+		Partitioned bool `json:"partitioned,omitempty"`
+	} `json:"props"`
+}
+
+// DatabaseSizes holds the sizes in bytes reported in DatabaseInfo.
+type DatabaseSizes struct {
+	File     int64 `json:"file"`
+	External int64 `json:"external"`
+	Active   int64 `json:"active"`
 }
 
 type ViewResponse struct {
@@ -46,6 +123,23 @@ type DesignDocument struct {
 	Language string                        `json:"language,omitempty"`
 	Views    map[string]DesignDocumentView `json:"views,omitempty"`
 	Filters  map[string]string             `json:"filters,omitempty"`
+	Indexes  map[string]SearchIndex        `json:"indexes,omitempty"` // Clouseau search indexes
+	Nouveau  map[string]NouveauIndex       `json:"nouveau,omitempty"` // Nouveau search indexes
+}
+
+// SearchIndex declares a Clouseau search index of a design document.
+// http://docs.couchdb.org/en/stable/ddocs/search.html
+type SearchIndex struct {
+	Analyzer interface{} `json:"analyzer,omitempty"` // Analyzer name or {"name": "perfield", ...} object
+	Index    string      `json:"index"`              // JavaScript function calling index()
+}
+
+// NouveauIndex declares a Nouveau search index of a design document.
+// http://docs.couchdb.org/en/stable/ddocs/nouveau.html
+type NouveauIndex struct {
+	DefaultAnalyzer string            `json:"default_analyzer,omitempty"`
+	FieldAnalyzers  map[string]string `json:"field_analyzers,omitempty"`
+	Index           string            `json:"index"` // JavaScript function calling index()
 }
 
 // Name returns design document name without the "_design/" prefix
@@ -72,6 +166,7 @@ type Document struct {
 	ID          string                `json:"_id,omitempty"`
 	Rev         string                `json:"_rev,omitempty"`
 	Attachments map[string]Attachment `json:"_attachments,omitempty"`
+	Conflicts   []string              `json:"_conflicts,omitempty"` // Set when read with GetOptions.Conflicts
 }
 
 // Attachment describes attachments of a document.
@@ -137,7 +232,7 @@ type FindArgs struct {
 	Skip           int64                  `json:"skip,omitempty"`            // – Skip the first ‘n’ results, where ‘n’ is the value specified. Optional
 	Sort           []interface{}          `json:"sort,omitempty"`            // – 排序 JSON array following sort syntax. Optional
 	Fields         []interface{}          `json:"fields,omitempty"`          // – 过滤 JSON array specifying which fields of each object should be returned. If it is omitted, the entire object is returned. More information provided in the section on filtering fields. Optional
-	UseIndex       string                 `json:"use_index,omitempty"`       //  索引( string|array)  – Instruct a query to use a specific index. Specified either as "<design_document>" or ["<design_document>", "<index_name>"]. Optional
+	UseIndex       interface{}            `json:"use_index,omitempty"`       //  索引( string|array)  – Instruct a query to use a specific index. Specified either as "<design_document>" or ["<design_document>", "<index_name>"]. Optional
 	R              int64                  `json:"r,omitempty"`               // (number)   – Read quorum needed for the result. This defaults to 1, in which case the document found in the index is returned. If set to a higher value, each document is read from at least that many replicas before it is returned in the results. This is likely to take more time than using only the document stored locally with the index. Optional, default: 1
 	Bookmark       string                 `json:"bookmark,omitempty"`        //  – A string that enables you to specify which page of results you require. Used for paging through result sets. Every query returns an opaque string under the bookmark key that can then be passed back in a query to get the next page of results. If any part of the selector query changes between requests, the results are undefined. Optional, default: null
 	Update         bool                   `json:"update,omitempty"`          //(boolean) – Whether to update the index prior to returning the result. Default is true. Optional
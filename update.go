@@ -0,0 +1,76 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// DefaultUpdateRetries is the number of times Update retries after a conflict by default.
+const DefaultUpdateRetries = 10
+
+// UpdateOptions configure Update.
+type UpdateOptions struct {
+	// MaxRetries limits how often the update is reapplied after a conflict.
+	// Zero means DefaultUpdateRetries and a negative value disables retries.
+	MaxRetries int
+}
+
+// UpdateConflictError is returned by Update when every attempt to store
+// the document ended in a conflict with a concurrent change.
+type UpdateConflictError struct {
+	ID       string
+	Attempts int
+	Err      error // The conflict of the last attempt
+}
+
+func (e *UpdateConflictError) Error() string {
+	return fmt.Sprintf("couchdb: update %s: conflict persisted after %d attempts: %v", e.ID, e.Attempts, e.Err)
+}
+
+func (e *UpdateConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Update reads document id into doc, applies update to it and stores it with
+// the revision it read, so concurrent changes are never overwritten. On a
+// conflict it reads the document again and reapplies update, see UpdateOptions.
+// doc must be a pointer; it is reset before every read. An error returned by
+// update aborts the update and is returned as is.
+func (db *Database) Update(ctx context.Context, id string, doc CouchDoc, update func(doc CouchDoc) error, opts *UpdateOptions) (*DocumentResponse, error) {
+	v := reflect.ValueOf(doc)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, errors.New("couchdb: Update requires a non-nil pointer document")
+	}
+	retries := DefaultUpdateRetries
+	if opts != nil && opts.MaxRetries != 0 {
+		retries = opts.MaxRetries
+	}
+	if retries < 0 {
+		retries = 0
+	}
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+		if err := db.GetInto(ctx, id, doc); err != nil {
+			return nil, err
+		}
+		rev := doc.GetRev()
+		if err := update(doc); err != nil {
+			return nil, err
+		}
+		doc.SetID(id)
+		doc.SetRev(rev)
+		var response *DocumentResponse
+		response, err = db.PutContext(ctx, doc)
+		if err == nil {
+			doc.SetRev(response.Rev)
+			return response, nil
+		}
+		if !errors.Is(err, ErrConflict) {
+			return nil, err
+		}
+	}
+	return nil, &UpdateConflictError{ID: id, Attempts: retries + 1, Err: err}
+}
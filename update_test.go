@@ -0,0 +1,95 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+type counterDoc struct {
+	Document
+	Count int    `json:"count"`
+	Note  string `json:"note,omitempty"`
+}
+
+// conflictingServer serves document "a" and answers the first conflicts PUTs with 409.
+func conflictingServer(t *testing.T, conflicts int, gets, puts *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			*gets++
+			w.Write([]byte(`{"_id":"a","_rev":"1-x","count":1}`))
+		case "PUT":
+			*puts++
+			if got := r.URL.Query().Get("rev"); got != "1-x" {
+				t.Errorf("PUT rev = %q, want 1-x", got)
+			}
+			if *puts <= conflicts {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"ok":true,"id":"a","rev":"2-y"}`))
+		}
+	}
+}
+
+func TestUpdateRetriesConflicts(t *testing.T) {
+	var gets, puts int
+	db := testDatabase(t, conflictingServer(t, 2, &gets, &puts))
+	doc := &counterDoc{Note: "stale"}
+	resp, err := db.Update(context.Background(), "a", doc, func(CouchDoc) error {
+		doc.Count++
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gets != 3 || puts != 3 {
+		t.Errorf("%d GETs and %d PUTs, want 3 each", gets, puts)
+	}
+	if resp.Rev != "2-y" || doc.Rev != "2-y" {
+		t.Errorf("rev = %q, doc rev = %q, want 2-y", resp.Rev, doc.Rev)
+	}
+	if doc.Count != 2 || doc.Note != "" {
+		t.Errorf("doc = %+v, want count 2 from a fresh read", doc)
+	}
+}
+
+func TestUpdateExhausted(t *testing.T) {
+	var gets, puts int
+	db := testDatabase(t, conflictingServer(t, 100, &gets, &puts))
+	_, err := db.Update(context.Background(), "a", &counterDoc{}, func(CouchDoc) error { return nil }, &UpdateOptions{MaxRetries: 2})
+	var cerr *UpdateConflictError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("err = %v, want *UpdateConflictError", err)
+	}
+	if cerr.ID != "a" || cerr.Attempts != 3 || puts != 3 {
+		t.Errorf("err = %+v after %d PUTs, want 3 attempts", cerr, puts)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("err = %v does not match ErrConflict", err)
+	}
+}
+
+func TestUpdateNoRetries(t *testing.T) {
+	var gets, puts int
+	db := testDatabase(t, conflictingServer(t, 1, &gets, &puts))
+	_, err := db.Update(context.Background(), "a", &counterDoc{}, func(CouchDoc) error { return nil }, &UpdateOptions{MaxRetries: -1})
+	var cerr *UpdateConflictError
+	if !errors.As(err, &cerr) || puts != 1 {
+		t.Errorf("err = %v after %d PUTs, want *UpdateConflictError after 1", err, puts)
+	}
+}
+
+func TestUpdateCallbackError(t *testing.T) {
+	var gets, puts int
+	db := testDatabase(t, conflictingServer(t, 0, &gets, &puts))
+	errStop := errors.New("stop")
+	_, err := db.Update(context.Background(), "a", &counterDoc{}, func(CouchDoc) error { return errStop }, nil)
+	if err != errStop || puts != 0 {
+		t.Errorf("err = %v after %d PUTs, want errStop and no PUT", err, puts)
+	}
+}
@@ -1,9 +1,9 @@
 package couchdb
 
 import (
+	"context"
+	"errors"
 	"fmt"
-
-	"github.com/google/go-querystring/query"
 )
 
 // View performs actions and certain view documents
@@ -14,32 +14,63 @@ type View struct {
 
 // Get executes specified view function from specified design document.
 func (v *View) Get(name string, params QueryParameters) (*ViewResponse, error) {
-	q, err := query.Values(params)
-	if err != nil {
-		return nil, err
-	}
-	uri := fmt.Sprintf("%s_view/%s?%s", v.URL, name, q.Encode())
+	return v.GetContext(context.Background(), name, params)
+}
+
+// GetContext is like Get but uses ctx for the request.
+func (v *View) GetContext(ctx context.Context, name string, params QueryParameters) (*ViewResponse, error) {
 	response := &ViewResponse{}
-	err = v.Client.Get(uri, response)
+	err := v.GetInto(ctx, name, params, response)
 	return response, err
 }
 
+// GetInto is like GetContext but decodes the response into out.
+func (v *View) GetInto(ctx context.Context, name string, params QueryParameters, out interface{}) error {
+	method, uri, in, err := params.request(fmt.Sprintf("%s_view/%s", v.URL, name))
+	if err != nil {
+		return err
+	}
+	return v.Client.do(ctx, uri, method, in, out)
+}
+
 // Post executes specified view function from specified design document.
 // Unlike View.Get for accessing views, View.Post supports
 // the specification of explicit keys to be retrieved from the view results.
 func (v *View) Post(name string, keys []string, params QueryParameters) (*ViewResponse, error) {
+	return v.PostContext(context.Background(), name, keys, params)
+}
+
+// PostContext is like Post but uses ctx for the request.
+func (v *View) PostContext(ctx context.Context, name string, keys []string, params QueryParameters) (*ViewResponse, error) {
+	response := &ViewResponse{}
+	err := v.PostInto(ctx, name, keys, params, response)
+	return response, err
+}
+
+// PostInto is like PostContext but decodes the response into out.
+func (v *View) PostInto(ctx context.Context, name string, keys []string, params QueryParameters, out interface{}) error {
+	content, url, err := v.postRequest(name, keys, params)
+	if err != nil {
+		return err
+	}
+	return v.Client.PostContext(ctx, url, content, out)
+}
+
+// postRequest returns body and URL of a POST request for keys. Since keys
+// are the body, params.Keys is rejected rather than silently dropped.
+func (v *View) postRequest(name string, keys []string, params QueryParameters) (interface{}, string, error) {
+	if params.Keys != nil {
+		return nil, "", errors.New("couchdb: pass keys as argument of Post instead of QueryParameters.Keys")
+	}
 	content := struct {
 		Keys []string `json:"keys"`
 	}{
 		Keys: keys,
 	}
 	// create query string
-	q, err := query.Values(params)
+	url, err := params.url(fmt.Sprintf("%s_view/%s", v.URL, name))
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	url := fmt.Sprintf("%s_view/%s?%s", v.URL, name, q.Encode())
-	response := &ViewResponse{}
-	err = v.Client.Post(url, content, response)
-	return response, err
+	return content, url, nil
 }